@@ -3,13 +3,12 @@ package tendermint
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path"
 	"sync"
 	"time"
 
-	"github.com/avast/retry-go"
-	"github.com/cometbft/cometbft/libs/log"
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
 	libclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
@@ -26,16 +25,11 @@ import (
 	authTypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	"github.com/cosmos/go-bip39"
 	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	committypes "github.com/cosmos/ibc-go/v7/modules/core/23-commitment/types"
 	ibcexported "github.com/cosmos/ibc-go/v7/modules/core/exported"
 
 	"github.com/hyperledger-labs/yui-relayer/core"
-)
-
-var (
-	rtyAttNum = uint(5)
-	rtyAtt    = retry.Attempts(rtyAttNum)
-	rtyDel    = retry.Delay(time.Millisecond * 400)
-	rtyErr    = retry.LastErrorOnly(true)
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Chain represents the necessary data for connecting to and indentifying a chain and its counterparites
@@ -51,15 +45,33 @@ type Chain struct {
 	codec            codec.ProtoCodecMarshaler `yaml:"-" json:"-"`
 	msgEventListener core.MsgEventListener
 
-	logger  log.Logger
-	timeout time.Duration
-	debug   bool
+	logger             *slog.Logger
+	timeout            time.Duration
+	txInclusionTimeout time.Duration
+	debug              bool
 
 	// stores facuet addresses that have been used reciently
 	faucetAddrs map[string]time.Time
+
+	// signers of the transactions, initialized on the first transaction
+	keys     *keyPool
+	keysOnce sync.Once
+	keysErr  error
+
+	// determines the gas prices of the transactions
+	gasPrices *gasPriceOracle
+
+	// retries the queries of the light client, the default policy is used if nil
+	retryPolicy *core.RetryPolicy
 }
 
 var _ core.Chain = (*Chain)(nil)
+var _ core.RetryPolicySetter = (*Chain)(nil)
+var _ core.ICS29Querier = (*Chain)(nil)
+var _ core.ClientUpdateQuerier = (*Chain)(nil)
+var _ core.CommitmentPrefixer = (*Chain)(nil)
+var _ core.MsgSimulator = (*Chain)(nil)
+var _ core.TxStatusQuerier = (*Chain)(nil)
 
 func (c *Chain) ChainID() string {
 	return c.config.ChainId
@@ -69,6 +81,19 @@ func (c *Chain) Config() ChainConfig {
 	return c.config
 }
 
+// CommitmentPrefix returns the prefix under which the IBC states of the chain are committed
+func (c *Chain) CommitmentPrefix() committypes.MerklePrefix {
+	return committypes.NewMerklePrefix([]byte(c.storeKey()))
+}
+
+// storeKey returns the key of the store where the IBC states are committed
+func (c *Chain) storeKey() string {
+	if c.config.CommitmentPrefix != "" {
+		return c.config.CommitmentPrefix
+	}
+	return ibcexported.StoreKey
+}
+
 func (c *Chain) ClientID() string {
 	return c.PathEnd.ClientID
 }
@@ -90,6 +115,11 @@ func (c *Chain) GetAddress() (sdk.AccAddress, error) {
 	return srcAddr.GetAddress()
 }
 
+// SetRetryPolicy sets the retry policy of the chain
+func (c *Chain) SetRetryPolicy(policy *core.RetryPolicy) {
+	c.retryPolicy = policy
+}
+
 // SetRelayInfo sets source's path and counterparty's info to the chain
 func (c *Chain) SetRelayInfo(p *core.PathEnd, _ *core.ProvableChain, _ *core.PathEnd) error {
 	if err := p.Validate(); err != nil {
@@ -108,7 +138,7 @@ func (c *Chain) Path() *core.PathEnd {
 	return c.PathEnd
 }
 
-func (c *Chain) Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, debug bool) error {
+func (c *Chain) Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, logger *slog.Logger, debug bool) error {
 	keybase, err := keys.New(c.config.ChainId, "test", keysDir(homePath, c.config.ChainId), nil, codec)
 	if err != nil {
 		return err
@@ -119,19 +149,25 @@ func (c *Chain) Init(homePath string, timeout time.Duration, codec codec.ProtoCo
 		return err
 	}
 
-	_, err = sdk.ParseDecCoins(c.config.GasPrices)
-	if err != nil {
-		return fmt.Errorf("failed to parse gas prices (%s) for chain %s", c.config.GasPrices, c.ChainID())
+	txInclusionTimeout := defaultTxInclusionTimeout
+	if c.config.TxInclusionTimeout != "" {
+		if txInclusionTimeout, err = time.ParseDuration(c.config.TxInclusionTimeout); err != nil {
+			return fmt.Errorf("failed to parse tx inclusion timeout (%s) for chain %s: %w", c.config.TxInclusionTimeout, c.ChainID(), err)
+		}
 	}
 
 	c.Keybase = keybase
 	c.Client = client
 	c.HomePath = homePath
 	c.codec = codec
-	c.logger = defaultChainLogger()
+	c.logger = logger
 	c.timeout = timeout
+	c.txInclusionTimeout = txInclusionTimeout
 	c.debug = debug
 	c.faucetAddrs = make(map[string]time.Time)
+	if c.gasPrices, err = newGasPriceOracle(c); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -140,8 +176,8 @@ func (c *Chain) SetupForRelay(ctx context.Context) error {
 }
 
 // LatestHeight queries the chain for the latest height and returns it
-func (c *Chain) LatestHeight() (ibcexported.Height, error) {
-	res, err := c.Client.Status(context.Background())
+func (c *Chain) LatestHeight(ctx context.Context) (ibcexported.Height, error) {
+	res, err := c.Client.Status(ctx)
 	if err != nil {
 		return nil, err
 	} else if res.SyncInfo.CatchingUp {
@@ -151,41 +187,126 @@ func (c *Chain) LatestHeight() (ibcexported.Height, error) {
 	return clienttypes.NewHeight(version, uint64(res.SyncInfo.LatestBlockHeight)), nil
 }
 
+// Timestamp returns the block timestamp at the given height
+func (c *Chain) Timestamp(ctx context.Context, height ibcexported.Height) (time.Time, error) {
+	ht := int64(height.GetRevisionHeight())
+	if header, err := c.Client.Header(ctx, &ht); err != nil {
+		return time.Time{}, err
+	} else {
+		return header.Header.Time, nil
+	}
+}
+
 // RegisterMsgEventListener registers a given EventListener to the chain
 func (c *Chain) RegisterMsgEventListener(listener core.MsgEventListener) {
 	c.msgEventListener = listener
 }
 
-func (c *Chain) sendMsgs(msgs []sdk.Msg) (*sdk.TxResponse, error) {
-	res, _, err := c.rawSendMsgs(msgs)
+// sendMsgs broadcasts a transaction of the msgs and waits for its inclusion.
+// The transaction is re-simulated and resubmitted if it is dropped from the mempool.
+func (c *Chain) sendMsgs(ctx context.Context, msgs []sdk.Msg) (_ *sdk.TxResponse, err error) {
+	ctx, span := core.StartChainSpan(ctx, "SendMsgs", c, attribute.Int("msgs", len(msgs)))
+	defer func() { core.EndSpan(span, err) }()
+	pool, s, msgs, err := c.acquireSigner(msgs)
 	if err != nil {
 		return nil, err
 	}
-	if res.Code == 0 && c.msgEventListener != nil {
-		if err := c.msgEventListener.OnSentMsg(msgs); err != nil {
-			c.logger.Error("failed to OnSendMsg call", "msgs", msgs, "err", err)
+	defer pool.release(s)
+
+	for resubmissions := 0; ; resubmissions++ {
+		res, err := c.rawSendMsgs(ctx, s, msgs)
+		if err != nil {
+			return nil, err
+		} else if res.Code != 0 {
+			// the transaction is rejected in CheckTx
 			return res, nil
 		}
+
+		resTx, err := c.waitForInclusion(ctx, res.TxHash)
+		if err != nil {
+			return res, err
+		} else if resTx == nil {
+			if resubmissions >= maxTxResubmissions {
+				return res, fmt.Errorf("transaction %s was dropped from the mempool %d times", res.TxHash, resubmissions+1)
+			}
+			c.pathLogger().Info("transaction was dropped from the mempool, resubmitting", "tx_hash", res.TxHash, "resubmissions", resubmissions+1)
+			// the sequences of the dropped transaction and the ones after it are no longer valid
+			s.sequencer.invalidate()
+			continue
+		}
+
+		res = sdk.NewResponseResultTx(resTx, nil, "")
+		if res.Code == 0 && c.msgEventListener != nil {
+			if err := c.msgEventListener.OnSentMsg(msgs); err != nil {
+				c.pathLogger().Error("failed to OnSendMsg call", "msgs", getMsgAction(msgs), "tx_hash", res.TxHash, "error", err)
+			}
+		}
+		return res, nil
 	}
-	return res, nil
 }
 
-func (c *Chain) rawSendMsgs(msgs []sdk.Msg) (*sdk.TxResponse, bool, error) {
+// rawSendMsgs broadcasts a transaction of the msgs signed by the signer and returns the result of CheckTx.
+// The account sequence is resynced with the node and the transaction is rebuilt once if the sequence mismatches.
+// The transaction is also rebuilt with raised gas prices while the fee is insufficient and the prices can be raised.
+func (c *Chain) rawSendMsgs(ctx context.Context, s *signer, msgs []sdk.Msg) (*sdk.TxResponse, error) {
+	// broadcasts are serialized so that the transactions arrive at the mempool in the order of their sequences
+	s.sequencer.mtx.Lock()
+	defer s.sequencer.mtx.Unlock()
+
+	resynced := false
+	for {
+		res, err := c.broadcastMsgs(ctx, s, msgs)
+		if err != nil {
+			s.sequencer.synced = false
+			// the simulation also fails if the sequence mismatches
+			if !resynced && isSequenceMismatchError(err) {
+				resynced = true
+				continue
+			}
+			return nil, err
+		}
+		if isSequenceMismatch(res.Codespace, res.Code) && !resynced {
+			c.pathLogger().Info("account sequence mismatch, resyncing", "signer", s.key, "raw_log", res.RawLog)
+			s.sequencer.synced = false
+			resynced = true
+			continue
+		}
+		if isInsufficientFee(res.Codespace, res.Code) && c.gasPrices.raise() {
+			c.pathLogger().Info("insufficient fee, raising gas prices", "raw_log", res.RawLog)
+			continue
+		}
+		if res.Code == 0 {
+			s.sequencer.sequence++
+		}
+		return res, nil
+	}
+}
+
+// broadcastMsgs builds, signs and broadcasts a transaction of the msgs with the cached account sequence of the signer
+func (c *Chain) broadcastMsgs(ctx context.Context, s *signer, msgs []sdk.Msg) (*sdk.TxResponse, error) {
 	// Instantiate the client context
-	ctx := c.CLIContext(0)
+	clientCtx := c.CLIContext(0).
+		WithFrom(s.key).
+		WithFromName(s.key).
+		WithFromAddress(s.address)
 
-	// Query account details
-	txf, err := prepareFactory(ctx, c.TxFactory(0))
+	// Get account details
+	txf, err := s.sequencer.factory(clientCtx, c.TxFactory(0))
 	if err != nil {
-		return nil, false, err
+		return nil, err
+	}
+	gasPrices, err := c.gasPrices.current()
+	if err != nil {
+		return nil, err
 	}
+	txf = txf.WithGasPrices(gasPrices.String())
 
 	// TODO: Make this work with new CalculateGas method
 	// https://github.com/cosmos/cosmos-sdk/blob/5725659684fc93790a63981c653feee33ecf3225/client/tx/tx.go#L297
 	// If users pass gas adjustment, then calculate gas
-	_, adjusted, err := CalculateGas(ctx.QueryWithData, txf, msgs...)
+	_, adjusted, err := c.calculateGas(ctx, clientCtx, txf, msgs)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
 	// Set the gas amount on the transaction factory
@@ -194,37 +315,82 @@ func (c *Chain) rawSendMsgs(msgs []sdk.Msg) (*sdk.TxResponse, bool, error) {
 	// Build the transaction builder
 	txb, err := txf.BuildUnsignedTx(msgs...)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
 	// Attach the signature to the transaction
-	err = tx.Sign(txf, c.config.Key, txb, false)
+	err = tx.Sign(txf, s.key, txb, false)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
 	// Generate the transaction bytes
-	txBytes, err := ctx.TxConfig.TxEncoder()(txb.GetTx())
+	txBytes, err := clientCtx.TxConfig.TxEncoder()(txb.GetTx())
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
 	// Broadcast those bytes
-	res, err := ctx.BroadcastTx(txBytes)
+	_, span := core.StartChainSpan(ctx, "BroadcastTx", c, attribute.Int64("sequence", int64(txf.Sequence())))
+	res, err := clientCtx.BroadcastTx(txBytes)
+	if err == nil {
+		span.SetAttributes(attribute.String("tx_hash", res.TxHash), attribute.Int64("code", int64(res.Code)))
+	}
+	core.EndSpan(span, err)
+	return res, err
+}
+
+// calculateGas simulates the transaction of the msgs in a span and returns the simulation response and the adjusted gas amount
+func (c *Chain) calculateGas(ctx context.Context, clientCtx sdkCtx.Context, txf tx.Factory, msgs []sdk.Msg) (_ txtypes.SimulateResponse, _ uint64, err error) {
+	_, span := core.StartChainSpan(ctx, "CalculateGas", c, attribute.Int("msgs", len(msgs)))
+	defer func() { core.EndSpan(span, err) }()
+	simRes, adjusted, err := CalculateGas(clientCtx.QueryWithData, txf, msgs...)
+	if err == nil {
+		span.SetAttributes(attribute.Int64("gas_used", int64(simRes.GasInfo.GasUsed)), attribute.Int64("gas", int64(adjusted)))
+	}
+	return simRes, adjusted, err
+}
+
+// SimulateMsgs simulates a transaction of the msgs with the signer and the gas prices that Send would use
+func (c *Chain) SimulateMsgs(ctx context.Context, msgs []sdk.Msg) (*core.SimulationResult, error) {
+	pool, s, msgs, err := c.acquireSigner(msgs)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
+	defer pool.release(s)
 
-	// transaction was executed, log the success or failure using the tx response code
-	// NOTE: error is nil, logic should use the returned error to determine if the
-	// transaction was successfully executed.
-	if res.Code != 0 {
-		c.LogFailedTx(res, err, msgs)
-		return res, false, nil
+	clientCtx := c.CLIContext(0).
+		WithFrom(s.key).
+		WithFromName(s.key).
+		WithFromAddress(s.address)
+
+	s.sequencer.mtx.Lock()
+	txf, err := s.sequencer.factory(clientCtx, c.TxFactory(0))
+	s.sequencer.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	gasPrices, err := c.gasPrices.current()
+	if err != nil {
+		return nil, err
 	}
 
-	c.LogSuccessTx(res, msgs)
-	return res, true, nil
+	simRes, adjusted, err := c.calculateGas(ctx, clientCtx, txf, msgs)
+	if err != nil {
+		return nil, err
+	}
+
+	// the fee is computed in the same way as tx.Factory does from the gas prices
+	gas := sdk.NewDec(int64(adjusted))
+	var fee sdk.Coins
+	for _, gp := range gasPrices {
+		fee = fee.Add(sdk.NewCoin(gp.Denom, gp.Amount.Mul(gas).Ceil().RoundInt()))
+	}
+	return &core.SimulationResult{
+		GasUsed: simRes.GasInfo.GasUsed,
+		Gas:     adjusted,
+		Fee:     fee,
+	}, nil
 }
 
 func prepareFactory(clientCtx sdkCtx.Context, txf tx.Factory) (tx.Factory, error) {
@@ -314,25 +480,61 @@ func CalculateGas(
 	return simRes, uint64(txf.GasAdjustment() * float64(simRes.GasInfo.GasUsed)), nil
 }
 
-func (c *Chain) SendMsgs(msgs []sdk.Msg) ([]byte, error) {
+func (c *Chain) SendMsgs(ctx context.Context, msgs []sdk.Msg) ([]byte, error) {
 	// Broadcast those bytes
-	res, err := c.sendMsgs(msgs)
+	res, err := c.sendMsgs(ctx, msgs)
 	if err != nil {
 		return nil, err
 	}
 	return []byte(res.Logs.String()), nil
 }
 
-func (c *Chain) Send(msgs []sdk.Msg) bool {
-	res, err := c.sendMsgs(msgs)
+func (c *Chain) Send(ctx context.Context, msgs []sdk.Msg) (*core.TxResult, error) {
+	res, err := c.sendMsgs(ctx, msgs)
 	if err != nil || res.Code != 0 {
 		c.LogFailedTx(res, err, msgs)
-		return false
+		if err == nil {
+			err = fmt.Errorf("transaction %s failed with code %d: %s", res.TxHash, res.Code, res.RawLog)
+		}
+		if res == nil {
+			return nil, err
+		}
+		return txResult(res), err
 	}
 	// NOTE: Add more data to this such as identifiers
 	c.LogSuccessTx(res, msgs)
 
-	return true
+	return txResult(res), nil
+}
+
+func txResult(res *sdk.TxResponse) *core.TxResult {
+	return &core.TxResult{
+		TxHash:    res.TxHash,
+		Height:    res.Height,
+		Codespace: res.Codespace,
+		Code:      res.Code,
+		Log:       res.RawLog,
+		GasUsed:   res.GasUsed,
+		Fee:       txFee(res),
+	}
+}
+
+// txFee returns the fee deducted for the transaction, which the ante handler emits in the tx event
+func txFee(res *sdk.TxResponse) sdk.Coins {
+	for _, ev := range res.Events {
+		if ev.Type != sdk.EventTypeTx {
+			continue
+		}
+		for _, attr := range ev.Attributes {
+			if attr.Key != sdk.AttributeKeyFee {
+				continue
+			}
+			if fee, err := sdk.ParseCoinsNormalized(attr.Value); err == nil {
+				return fee
+			}
+		}
+	}
+	return nil
 }
 
 // ------------------------------- //
@@ -436,10 +638,6 @@ func newRPCClient(addr string, timeout time.Duration) (*rpchttp.HTTP, error) {
 	return rpcClient, nil
 }
 
-func defaultChainLogger() log.Logger {
-	return log.NewTMLogger(log.NewSyncWriter(os.Stdout))
-}
-
 // CreateMnemonic creates a new mnemonic
 func CreateMnemonic() (string, error) {
 	entropySeed, err := bip39.NewEntropy(256)
@@ -0,0 +1,121 @@
+package tendermint
+
+import (
+	"fmt"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+var _ core.CheckpointQuerier = (*Chain)(nil)
+
+// QueryPacketCommitmentSequences returns the sequences of the packet commitments on the path end
+func (c *Chain) QueryPacketCommitmentSequences(ctx core.QueryContext) ([]uint64, error) {
+	return c.queryPacketCommitmentSequences(ctx)
+}
+
+// QuerySentPackets returns the packets sent from the path end in the blocks from `fromHeight` to the height of `ctx`
+func (c *Chain) QuerySentPackets(ctx core.QueryContext, fromHeight uint64) (core.PacketInfoList, error) {
+	events := []string{
+		fmt.Sprintf("%s.packet_src_port='%s'", spTag, c.PathEnd.PortID),
+		fmt.Sprintf("%s.packet_src_channel='%s'", spTag, c.PathEnd.ChannelID),
+	}
+	var packets core.PacketInfoList
+	err := c.searchTxsInRange(ctx, fromHeight, events, func(tx *ctypes.ResultTx) error {
+		ps, err := core.GetPacketsFromEvents(tx.TxResult.Events, chantypes.EventTypeSendPacket)
+		if err != nil {
+			return err
+		}
+		height := clienttypes.NewHeight(clienttypes.ParseChainID(c.ChainID()), uint64(tx.Height))
+		for _, p := range ps {
+			if p.SourcePort != c.PathEnd.PortID || p.SourceChannel != c.PathEnd.ChannelID {
+				continue
+			}
+			packets = append(packets, &core.PacketInfo{
+				Packet:      p,
+				EventHeight: height,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return packets, nil
+}
+
+// QuerySentPacket returns the packet sent from the path end with the sequence
+func (c *Chain) QuerySentPacket(ctx core.QueryContext, seq uint64) (*core.PacketInfo, error) {
+	packet, height, err := c.querySentPacket(ctx, seq)
+	if err != nil {
+		return nil, err
+	}
+	return &core.PacketInfo{
+		Packet:      *packet,
+		EventHeight: height,
+	}, nil
+}
+
+// QueryPacketAcknowledgementSequences returns the sequences of the packet acknowledgement commitments on the path end
+func (c *Chain) QueryPacketAcknowledgementSequences(ctx core.QueryContext) ([]uint64, error) {
+	return c.queryPacketAcknowledgementSequences(ctx)
+}
+
+// QueryWrittenAcknowledgements returns the packets whose acknowledgements are written on the path end
+// in the blocks from `fromHeight` to the height of `ctx`
+func (c *Chain) QueryWrittenAcknowledgements(ctx core.QueryContext, fromHeight uint64) (core.PacketInfoList, error) {
+	events := []string{
+		fmt.Sprintf("%s.packet_dst_port='%s'", waTag, c.PathEnd.PortID),
+		fmt.Sprintf("%s.packet_dst_channel='%s'", waTag, c.PathEnd.ChannelID),
+	}
+	var packets core.PacketInfoList
+	err := c.searchTxsInRange(ctx, fromHeight, events, func(tx *ctypes.ResultTx) error {
+		// packets and acknowledgements are parsed from the same events, so they are in the same order
+		ps, err := core.GetPacketsFromEvents(tx.TxResult.Events, chantypes.EventTypeWriteAck)
+		if err != nil {
+			return err
+		}
+		acks, err := core.GetPacketAcknowledgementsFromEvents(tx.TxResult.Events)
+		if err != nil {
+			return err
+		}
+		if len(ps) != len(acks) {
+			return fmt.Errorf("mismatched number of packets and acknowledgements: %d != %d", len(ps), len(acks))
+		}
+		height := clienttypes.NewHeight(clienttypes.ParseChainID(c.ChainID()), uint64(tx.Height))
+		for i, p := range ps {
+			if p.DestinationPort != c.PathEnd.PortID || p.DestinationChannel != c.PathEnd.ChannelID {
+				continue
+			}
+			packets = append(packets, &core.PacketInfo{
+				Packet:          p,
+				Acknowledgement: acks[i].Data(),
+				EventHeight:     height,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return packets, nil
+}
+
+// QueryWrittenAcknowledgement returns the packet received by the path end with the sequence and its acknowledgement
+func (c *Chain) QueryWrittenAcknowledgement(ctx core.QueryContext, seq uint64) (*core.PacketInfo, error) {
+	packet, rpHeight, err := c.queryReceivedPacket(ctx, seq)
+	if err != nil {
+		return nil, err
+	}
+	ack, _, err := c.queryWrittenAcknowledgement(ctx, seq)
+	if err != nil {
+		return nil, err
+	}
+	return &core.PacketInfo{
+		Packet:          *packet,
+		Acknowledgement: ack,
+		EventHeight:     rpHeight,
+	}, nil
+}
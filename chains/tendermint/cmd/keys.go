@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
 	"github.com/hyperledger-labs/yui-relayer/config"
 	"github.com/spf13/cobra"
@@ -23,6 +26,7 @@ func keysCmd(ctx *config.Context) *cobra.Command {
 		keysRestoreCmd(ctx),
 		keysShowCmd(ctx),
 		keysListCmd(ctx),
+		keysPoolCmd(ctx),
 	)
 
 	return cmd
@@ -198,3 +202,86 @@ func keysListCmd(ctx *config.Context) *cobra.Command {
 
 	return cmd
 }
+
+// keysPoolCmd respresents the `keys pool` command
+func keysPoolCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pool [chain-id] [[amount]]",
+		Short: "creates the pool keys configured for a particular chain and funds them from the primary key",
+		Long: `Creates the keys listed in the "keys" field of the chain config that don't exist in the keychain yet,
+and if an amount (e.g. 1000stake) is given, sends it to each of the pool keys from the primary key.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := ctx.Config.GetChain(args[0])
+			if err != nil {
+				return err
+			}
+			chain := c.Chain.(*tendermint.Chain)
+
+			if len(chain.PoolKeys()) == 0 {
+				return fmt.Errorf("no pool keys are configured for chain %s", chain.ChainID())
+			}
+
+			var amount sdk.Coins
+			if len(args) == 2 {
+				if amount, err = sdk.ParseCoinsNormalized(args[1]); err != nil {
+					return err
+				}
+			}
+
+			var addrs []sdk.AccAddress
+			for _, keyName := range chain.PoolKeys() {
+				if chain.KeyExists(keyName) {
+					info, err := chain.Keybase.Key(keyName)
+					if err != nil {
+						return err
+					}
+					addr, err := info.GetAddress()
+					if err != nil {
+						return err
+					}
+					addrs = append(addrs, addr)
+					continue
+				}
+
+				mnemonic, err := tendermint.CreateMnemonic()
+				if err != nil {
+					return err
+				}
+				info, err := chain.Keybase.NewAccount(keyName, mnemonic, "", hd.CreateHDPath(118, 0, 0).String(), hd.Secp256k1)
+				if err != nil {
+					return err
+				}
+				addr, err := info.GetAddress()
+				if err != nil {
+					return err
+				}
+				out, err := json.Marshal(&keyOutput{Mnemonic: mnemonic, Address: addr.String()})
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(out))
+				addrs = append(addrs, addr)
+			}
+
+			if amount.Empty() {
+				return nil
+			}
+			from, err := chain.GetAddress()
+			if err != nil {
+				return err
+			}
+			var msgs []sdk.Msg
+			for _, addr := range addrs {
+				msgs = append(msgs, banktypes.NewMsgSend(from, addr, amount))
+			}
+			// a broadcast is not interrupted halfway so that the sequence of the signer stays consistent
+			if _, err := chain.Send(context.WithoutCancel(cmd.Context()), msgs); err != nil {
+				return fmt.Errorf("failed to fund the pool keys: %w", err)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -31,6 +31,18 @@ type ChainConfig struct {
 	AccountPrefix string  `protobuf:"bytes,4,opt,name=account_prefix,json=accountPrefix,proto3" json:"account_prefix,omitempty"`
 	GasAdjustment float64 `protobuf:"fixed64,5,opt,name=gas_adjustment,json=gasAdjustment,proto3" json:"gas_adjustment,omitempty"`
 	GasPrices     string  `protobuf:"bytes,6,opt,name=gas_prices,json=gasPrices,proto3" json:"gas_prices,omitempty"`
+	// the key of the store where the IBC states are committed (default: "ibc")
+	CommitmentPrefix string `protobuf:"bytes,7,opt,name=commitment_prefix,json=commitmentPrefix,proto3" json:"commitment_prefix,omitempty"`
+	// the maximum time to wait for a transaction to be included in a block (default: "1m")
+	TxInclusionTimeout string `protobuf:"bytes,8,opt,name=tx_inclusion_timeout,json=txInclusionTimeout,proto3" json:"tx_inclusion_timeout,omitempty"`
+	// additional keys that sign relay transactions in parallel with `key`
+	Keys []string `protobuf:"bytes,9,rep,name=keys,proto3" json:"keys,omitempty"`
+	// where the gas prices are discovered: "static" (default) uses `gas_prices`,
+	// "node" queries the minimum gas prices of the node and "feemarket" queries the fee market module.
+	// With a dynamic source, `gas_prices` is the lower limit and selects the fee denoms if set.
+	GasPriceSource string `protobuf:"bytes,10,opt,name=gas_price_source,json=gasPriceSource,proto3" json:"gas_price_source,omitempty"`
+	// the upper limit to which the gas prices are raised on insufficient fee errors (no raise if empty)
+	MaxGasPrices string `protobuf:"bytes,11,opt,name=max_gas_prices,json=maxGasPrices,proto3" json:"max_gas_prices,omitempty"`
 }
 
 func (m *ChainConfig) Reset()         { *m = ChainConfig{} }
@@ -113,28 +125,34 @@ func init() {
 }
 
 var fileDescriptor_d67cd47cbc86ecb1 = []byte{
-	// 331 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0xb1, 0x4e, 0xeb, 0x30,
-	0x14, 0x86, 0xe3, 0xdb, 0x7b, 0x7b, 0xa9, 0xa1, 0x05, 0x45, 0x0c, 0x01, 0x89, 0xa8, 0xaa, 0x84,
-	0xe8, 0xd2, 0x64, 0x60, 0x40, 0x8c, 0xa5, 0x13, 0x5b, 0xd4, 0x05, 0x89, 0x25, 0x72, 0x6d, 0xd7,
-	0x35, 0xb4, 0xb6, 0x75, 0xec, 0x20, 0xf2, 0x16, 0x3c, 0x13, 0x53, 0xc7, 0x8e, 0x8c, 0xd0, 0xbe,
-	0x08, 0x8a, 0x13, 0x60, 0x62, 0xf2, 0xf1, 0x77, 0xbe, 0x73, 0xa4, 0xdf, 0xc6, 0x23, 0xe0, 0x4b,
-	0x52, 0x72, 0x48, 0xe9, 0x82, 0x48, 0x65, 0x53, 0xc7, 0x15, 0xe3, 0xb0, 0x92, 0xca, 0xa5, 0x54,
-	0xab, 0xb9, 0x14, 0xcd, 0x91, 0x18, 0xd0, 0x4e, 0x87, 0xfd, 0x46, 0x4f, 0x6a, 0x3d, 0xf9, 0xd1,
-	0x93, 0xda, 0x3b, 0x3d, 0x16, 0x5a, 0x68, 0x2f, 0xa7, 0x55, 0x55, 0xcf, 0x0d, 0x5e, 0x11, 0xde,
-	0x9f, 0x54, 0x23, 0x13, 0x6f, 0x85, 0x47, 0xb8, 0xf5, 0xc8, 0xcb, 0x08, 0xf5, 0xd1, 0xb0, 0x33,
-	0xad, 0xca, 0xf0, 0x04, 0xef, 0xf9, 0x9d, 0xb9, 0x64, 0xd1, 0x1f, 0x8f, 0xff, 0xfb, 0xfb, 0x2d,
-	0xab, 0x5a, 0x60, 0x68, 0x4e, 0x18, 0x83, 0xa8, 0x55, 0xb7, 0xc0, 0xd0, 0x31, 0x63, 0x10, 0x9e,
-	0xe3, 0x1e, 0xa1, 0x54, 0x17, 0xca, 0xe5, 0x06, 0xf8, 0x5c, 0x3e, 0x47, 0x7f, 0xbd, 0xd0, 0x6d,
-	0x68, 0xe6, 0x61, 0xa5, 0x09, 0x62, 0x73, 0xc2, 0x1e, 0x0a, 0xeb, 0x56, 0x5c, 0xb9, 0xe8, 0x5f,
-	0x1f, 0x0d, 0xd1, 0xb4, 0x2b, 0x88, 0x1d, 0x7f, 0xc3, 0xf0, 0x0c, 0xe3, 0x4a, 0x33, 0x20, 0x29,
-	0xb7, 0x51, 0xdb, 0x6f, 0xea, 0x08, 0x62, 0x33, 0x0f, 0x06, 0x57, 0xf8, 0x20, 0x03, 0xfd, 0xc4,
-	0xa1, 0x09, 0x71, 0x81, 0x0f, 0x1d, 0x14, 0xd6, 0x49, 0x25, 0x72, 0xc3, 0x41, 0x6a, 0xd6, 0x04,
-	0xea, 0x7d, 0xe1, 0xcc, 0xd3, 0x9b, 0xbb, 0xf5, 0x47, 0x1c, 0xac, 0xb7, 0x31, 0xda, 0x6c, 0x63,
-	0xf4, 0xbe, 0x8d, 0xd1, 0xcb, 0x2e, 0x0e, 0x36, 0xbb, 0x38, 0x78, 0xdb, 0xc5, 0xc1, 0xfd, 0xb5,
-	0x90, 0x6e, 0x51, 0xcc, 0x12, 0xaa, 0x57, 0xe9, 0xa2, 0x34, 0x1c, 0x96, 0x9c, 0x09, 0x0e, 0xa3,
-	0x25, 0x99, 0xd9, 0xb4, 0x2c, 0xe4, 0xef, 0x5f, 0x34, 0x6b, 0xfb, 0xd7, 0xbd, 0xfc, 0x0c, 0x00,
-	0x00, 0xff, 0xff, 0xa9, 0xcb, 0x98, 0xae, 0xc6, 0x01, 0x00, 0x00,
+	// 429 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0x41, 0x6f, 0xd3, 0x30,
+	0x14, 0xc7, 0x1b, 0x3a, 0xb6, 0xd5, 0xdb, 0x4a, 0xb1, 0x76, 0x08, 0x48, 0x44, 0xd5, 0x04, 0xa2,
+	0x12, 0x5a, 0x82, 0xc4, 0x01, 0x71, 0x1c, 0x3b, 0xa0, 0xdd, 0xaa, 0x82, 0x84, 0xc4, 0xc5, 0x72,
+	0x6d, 0xcf, 0x35, 0x6b, 0xec, 0xe8, 0xd9, 0x41, 0xc9, 0x07, 0xe0, 0xce, 0xc7, 0xda, 0x71, 0x47,
+	0x8e, 0xd0, 0x7e, 0x11, 0x94, 0x97, 0x64, 0xe5, 0xb2, 0x53, 0x5e, 0x7e, 0xff, 0x9f, 0x9f, 0x9f,
+	0x65, 0x93, 0x73, 0x50, 0x6b, 0x5e, 0x2b, 0xc8, 0xc4, 0x8a, 0x1b, 0xeb, 0xb3, 0xa0, 0xac, 0x54,
+	0x90, 0x1b, 0x1b, 0x32, 0xe1, 0xec, 0xb5, 0xd1, 0xdd, 0x27, 0x2d, 0xc0, 0x05, 0x47, 0xa7, 0x9d,
+	0x9e, 0xb6, 0x7a, 0xba, 0xd3, 0xd3, 0xd6, 0x7b, 0x7e, 0xaa, 0x9d, 0x76, 0x28, 0x67, 0x4d, 0xd5,
+	0xae, 0x3b, 0xfb, 0x39, 0x24, 0x47, 0x97, 0xcd, 0x92, 0x4b, 0xb4, 0xe8, 0x84, 0x0c, 0x6f, 0x54,
+	0x1d, 0x47, 0xd3, 0x68, 0x36, 0x5a, 0x34, 0x25, 0x7d, 0x46, 0x0e, 0xb1, 0x27, 0x33, 0x32, 0x7e,
+	0x84, 0xf8, 0x00, 0xff, 0xaf, 0x64, 0x13, 0x41, 0x21, 0x18, 0x97, 0x12, 0xe2, 0x61, 0x1b, 0x41,
+	0x21, 0x2e, 0xa4, 0x04, 0xfa, 0x8a, 0x8c, 0xb9, 0x10, 0xae, 0xb4, 0x81, 0x15, 0xa0, 0xae, 0x4d,
+	0x15, 0xef, 0xa1, 0x70, 0xd2, 0xd1, 0x39, 0xc2, 0x46, 0xd3, 0xdc, 0x33, 0x2e, 0xbf, 0x97, 0x3e,
+	0xe4, 0xca, 0x86, 0xf8, 0xf1, 0x34, 0x9a, 0x45, 0x8b, 0x13, 0xcd, 0xfd, 0xc5, 0x3d, 0xa4, 0x2f,
+	0x08, 0x69, 0xb4, 0x02, 0x8c, 0x50, 0x3e, 0xde, 0xc7, 0x4e, 0x23, 0xcd, 0xfd, 0x1c, 0x01, 0x7d,
+	0x43, 0x9e, 0x0a, 0x97, 0xe7, 0x06, 0xe5, 0x7e, 0xbf, 0x03, 0xb4, 0x26, 0xbb, 0xa0, 0xdb, 0xf2,
+	0x2d, 0x39, 0x0d, 0x15, 0x33, 0x56, 0xac, 0x4b, 0x6f, 0x9c, 0x65, 0xc1, 0xe4, 0xca, 0x95, 0x21,
+	0x3e, 0x44, 0x9f, 0x86, 0xea, 0xaa, 0x8f, 0xbe, 0xb4, 0x09, 0xa5, 0x64, 0xef, 0x46, 0xd5, 0x3e,
+	0x1e, 0x4d, 0x87, 0xb3, 0xd1, 0x02, 0x6b, 0x3a, 0x23, 0x93, 0xfb, 0x89, 0x98, 0x77, 0x25, 0x08,
+	0x15, 0x13, 0xec, 0x30, 0xee, 0xe7, 0xfa, 0x8c, 0x94, 0xbe, 0x24, 0xe3, 0x9c, 0x57, 0xec, 0xbf,
+	0xf9, 0x8f, 0xd0, 0x3b, 0xce, 0x79, 0xf5, 0xa9, 0x3f, 0xc2, 0xd9, 0x7b, 0x72, 0x3c, 0x07, 0xf7,
+	0x43, 0x41, 0x77, 0x0f, 0xaf, 0xc9, 0x93, 0x00, 0xa5, 0x0f, 0xc6, 0x6a, 0x56, 0x28, 0x30, 0x4e,
+	0x76, 0x77, 0x32, 0xee, 0xf1, 0x1c, 0xe9, 0xc7, 0xaf, 0xb7, 0x7f, 0x93, 0xc1, 0xed, 0x26, 0x89,
+	0xee, 0x36, 0x49, 0xf4, 0x67, 0x93, 0x44, 0xbf, 0xb6, 0xc9, 0xe0, 0x6e, 0x9b, 0x0c, 0x7e, 0x6f,
+	0x93, 0xc1, 0xb7, 0x0f, 0xda, 0x84, 0x55, 0xb9, 0x4c, 0x85, 0xcb, 0xb3, 0x55, 0x5d, 0x28, 0x58,
+	0x2b, 0xa9, 0x15, 0x9c, 0xaf, 0xf9, 0xd2, 0x67, 0x75, 0x69, 0x1e, 0x7e, 0x65, 0xcb, 0x7d, 0x7c,
+	0x20, 0xef, 0xfe, 0x0d, 0x00, 0x05, 0x80, 0x87, 0x3a, 0x89, 0x02, 0x00, 0x00,
 }
 
 func (m *ChainConfig) Marshal() (dAtA []byte, err error) {
@@ -157,6 +175,43 @@ func (m *ChainConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.MaxGasPrices) > 0 {
+		i -= len(m.MaxGasPrices)
+		copy(dAtA[i:], m.MaxGasPrices)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.MaxGasPrices)))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if len(m.GasPriceSource) > 0 {
+		i -= len(m.GasPriceSource)
+		copy(dAtA[i:], m.GasPriceSource)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.GasPriceSource)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.Keys) > 0 {
+		for iNdEx := len(m.Keys) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Keys[iNdEx])
+			copy(dAtA[i:], m.Keys[iNdEx])
+			i = encodeVarintConfig(dAtA, i, uint64(len(m.Keys[iNdEx])))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if len(m.TxInclusionTimeout) > 0 {
+		i -= len(m.TxInclusionTimeout)
+		copy(dAtA[i:], m.TxInclusionTimeout)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.TxInclusionTimeout)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.CommitmentPrefix) > 0 {
+		i -= len(m.CommitmentPrefix)
+		copy(dAtA[i:], m.CommitmentPrefix)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.CommitmentPrefix)))
+		i--
+		dAtA[i] = 0x3a
+	}
 	if len(m.GasPrices) > 0 {
 		i -= len(m.GasPrices)
 		copy(dAtA[i:], m.GasPrices)
@@ -271,6 +326,28 @@ func (m *ChainConfig) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovConfig(uint64(l))
 	}
+	l = len(m.CommitmentPrefix)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.TxInclusionTimeout)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.Keys) > 0 {
+		for _, s := range m.Keys {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	l = len(m.GasPriceSource)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.MaxGasPrices)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
 	return n
 }
 
@@ -493,6 +570,166 @@ func (m *ChainConfig) Unmarshal(dAtA []byte) error {
 			}
 			m.GasPrices = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitmentPrefix", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CommitmentPrefix = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxInclusionTimeout", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TxInclusionTimeout = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Keys", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Keys = append(m.Keys, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GasPriceSource", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GasPriceSource = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxGasPrices", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MaxGasPrices = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
@@ -0,0 +1,155 @@
+package tendermint
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+var _ core.RelayEventSubscriber = (*Chain)(nil)
+
+// SubscribeRelayEvents subscribes to NewBlock events and to send_packet/write_acknowledgement events of the path end
+// via the websocket endpoint. The returned channel receives a value on the first new block after relevant events.
+// The subscriptions are renewed with the backoff of the retry policy if the websocket closes them.
+func (c *Chain) SubscribeRelayEvents(ctx context.Context) (<-chan struct{}, error) {
+	if !c.Client.IsRunning() {
+		if err := c.Client.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start the websocket client: %w", err)
+		}
+	}
+
+	// the path end may be rebound to another path sharing the chain while the subscriptions are renewed, so it's read only here
+	q := relayEventQuery{
+		subscriber: fmt.Sprintf("yui-relayer-%s-%s", c.PathEnd.PortID, c.PathEnd.ChannelID),
+		portID:     c.PathEnd.PortID,
+		channelID:  c.PathEnd.ChannelID,
+		logger:     c.pathLogger(),
+	}
+	sub, err := c.subscribeRelayEvents(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan struct{}, 1)
+	go func() {
+		defer func() {
+			if err := c.Client.UnsubscribeAll(context.Background(), q.subscriber); err != nil {
+				q.logger.Error("failed to unsubscribe", "subscriber", q.subscriber, "error", err)
+			}
+		}()
+		for {
+			sub.forward(ctx, out)
+			if ctx.Err() != nil {
+				return
+			}
+			q.logger.Error("event subscriptions were closed, resubscribing", "subscriber", q.subscriber)
+			if sub, err = c.resubscribeRelayEvents(ctx, q); err != nil {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// relayEventQuery is the path end whose events are subscribed by SubscribeRelayEvents
+type relayEventQuery struct {
+	subscriber        string
+	portID, channelID string
+	logger            *slog.Logger
+}
+
+// relayEventSubscription is the channels of the events subscribed by SubscribeRelayEvents
+type relayEventSubscription struct {
+	newBlock, sendPacket, writeAck <-chan ctypes.ResultEvent
+}
+
+func (c *Chain) subscribeRelayEvents(ctx context.Context, q relayEventQuery) (*relayEventSubscription, error) {
+	subscribe := func(query string) (<-chan ctypes.ResultEvent, error) {
+		ch, err := c.Client.Subscribe(ctx, q.subscriber, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to subscribe to '%s': %w", query, err)
+		}
+		return ch, nil
+	}
+
+	var (
+		sub relayEventSubscription
+		err error
+	)
+	if sub.newBlock, err = subscribe(tmtypes.EventQueryNewBlock.String()); err != nil {
+		return nil, err
+	}
+	if sub.sendPacket, err = subscribe(sendPacketEventQuery(q.portID, q.channelID)); err != nil {
+		return nil, err
+	}
+	if sub.writeAck, err = subscribe(writeAckEventQuery(q.portID, q.channelID)); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// resubscribeRelayEvents renews the subscriptions until it succeeds or `ctx` is done, in which case the error of `ctx` is returned
+func (c *Chain) resubscribeRelayEvents(ctx context.Context, q relayEventQuery) (*relayEventSubscription, error) {
+	for n := uint(1); ; n++ {
+		if err := sleepContext(ctx, c.retryPolicy.Backoff(n)); err != nil {
+			return nil, err
+		}
+		// the subscriptions that are still open must be removed before subscribing to the same queries again
+		if err := c.Client.UnsubscribeAll(ctx, q.subscriber); err != nil {
+			q.logger.Info("failed to unsubscribe before resubscribing", "subscriber", q.subscriber, "error", err)
+		}
+		sub, err := c.subscribeRelayEvents(ctx, q)
+		if err == nil {
+			q.logger.Info("resubscribed to the events", "subscriber", q.subscriber, "attempts", n)
+			return sub, nil
+		}
+		q.logger.Error("failed to resubscribe to the events", "subscriber", q.subscriber, "attempt", n, "error", err)
+	}
+}
+
+// forward notifies `out` on the first new block after relevant events until `ctx` is done or any of the subscriptions is closed
+func (sub *relayEventSubscription) forward(ctx context.Context, out chan<- struct{}) {
+	pending := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-sub.sendPacket:
+			if !ok {
+				return
+			}
+			pending = true
+		case _, ok := <-sub.writeAck:
+			if !ok {
+				return
+			}
+			pending = true
+		case _, ok := <-sub.newBlock:
+			if !ok {
+				return
+			}
+			// wake the service up on the block after the relevant events so that they are committed
+			if pending {
+				pending = false
+				select {
+				case out <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}
+}
+
+func sendPacketEventQuery(portID, channelID string) string {
+	return fmt.Sprintf("%s='%s' AND %s.packet_src_port='%s' AND %s.packet_src_channel='%s'",
+		tmtypes.EventTypeKey, tmtypes.EventTx, spTag, portID, spTag, channelID)
+}
+
+func writeAckEventQuery(portID, channelID string) string {
+	return fmt.Sprintf("%s='%s' AND %s.packet_dst_port='%s' AND %s.packet_dst_channel='%s'",
+		tmtypes.EventTypeKey, tmtypes.EventTx, waTag, portID, waTag, channelID)
+}
@@ -0,0 +1,85 @@
+package tendermint
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// subscriptionClient records the subscribed queries and closes all the subscriptions on `drop`
+type subscriptionClient struct {
+	rpcclient.Client
+	mu      sync.Mutex
+	queries []string
+	chans   []chan ctypes.ResultEvent
+}
+
+func (c *subscriptionClient) IsRunning() bool { return true }
+
+func (c *subscriptionClient) Subscribe(_ context.Context, _, query string, _ ...int) (<-chan ctypes.ResultEvent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan ctypes.ResultEvent)
+	c.queries = append(c.queries, query)
+	c.chans = append(c.chans, ch)
+	return ch, nil
+}
+
+func (c *subscriptionClient) UnsubscribeAll(context.Context, string) error { return nil }
+
+func (c *subscriptionClient) drop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, ch := range c.chans {
+		close(ch)
+	}
+	c.chans = nil
+}
+
+func (c *subscriptionClient) subscribed() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.queries...)
+}
+
+func TestSubscribeRelayEventsResubscribesToThePathEnd(t *testing.T) {
+	client := &subscriptionClient{}
+	chain := &Chain{
+		Client:      client,
+		PathEnd:     &core.PathEnd{PortID: "transfer", ChannelID: "channel-0"},
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		retryPolicy: &core.RetryPolicy{InitialDelay: "1ms", MaxJitter: "0s"},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := chain.SubscribeRelayEvents(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the supervisor rebinds the chain to another path while the subscriptions are renewed
+	client.drop()
+	chain.PathEnd = &core.PathEnd{PortID: "transfer", ChannelID: "channel-1"}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(client.subscribed()) < 6 {
+		if time.Now().After(deadline) {
+			t.Fatal("events were not resubscribed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	queries := client.subscribed()
+	want := []string{
+		sendPacketEventQuery("transfer", "channel-0"),
+		writeAckEventQuery("transfer", "channel-0"),
+	}
+	if queries[4] != want[0] || queries[5] != want[1] {
+		t.Errorf("resubscribed to %v, want %v", queries[4:6], want)
+	}
+}
@@ -0,0 +1,224 @@
+package tendermint
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cosmos/cosmos-sdk/client/grpc/node"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	gasPriceSourceStatic    = "static"
+	gasPriceSourceNode      = "node"
+	gasPriceSourceFeeMarket = "feemarket"
+
+	// gasPriceRefreshInterval is the interval at which the gas prices are queried from a dynamic source,
+	// and after which the prices raised on an insufficient fee error fall back to the ones of the source
+	gasPriceRefreshInterval = time.Minute
+	// gasPriceRaiseRatio is the ratio by which the gas prices are raised on an insufficient fee error
+	gasPriceRaiseRatio = "1.5"
+
+	// feeMarketGasPricesPath is the query of the gas prices of the fee market module (github.com/skip-mev/feemarket)
+	feeMarketGasPricesPath = "/feemarket.feemarket.v1.Query/GasPrices"
+)
+
+// gasPriceOracle determines the gas prices of the transactions of a chain.
+// The prices are the ones of the source, limited by the configured lower and upper limits,
+// and are raised towards the upper limit when the chain rejects a transaction for an insufficient fee.
+type gasPriceOracle struct {
+	mtx sync.Mutex
+
+	chainID string
+	source  string
+	floor   sdk.DecCoins
+	ceiling sdk.DecCoins
+	query   func() (sdk.DecCoins, error) // nil for the static source
+	logger  *slog.Logger
+
+	queried     sdk.DecCoins
+	refreshedAt time.Time
+	raised      sdk.DecCoins
+	raisedAt    time.Time
+	used        sdk.DecCoins
+}
+
+func newGasPriceOracle(c *Chain) (*gasPriceOracle, error) {
+	floor, err := sdk.ParseDecCoins(c.config.GasPrices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gas prices (%s) for chain %s", c.config.GasPrices, c.ChainID())
+	}
+	ceiling, err := sdk.ParseDecCoins(c.config.MaxGasPrices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse max gas prices (%s) for chain %s", c.config.MaxGasPrices, c.ChainID())
+	}
+	o := &gasPriceOracle{
+		chainID: c.ChainID(),
+		source:  c.config.GasPriceSource,
+		floor:   floor,
+		ceiling: ceiling,
+		logger:  c.logger.With("chain_id", c.ChainID()),
+	}
+	switch o.source {
+	case "", gasPriceSourceStatic:
+		o.source = gasPriceSourceStatic
+	case gasPriceSourceNode:
+		o.query = c.queryNodeMinGasPrices
+	case gasPriceSourceFeeMarket:
+		o.query = c.queryFeeMarketGasPrices
+	default:
+		return nil, fmt.Errorf("unknown gas price source (%s) for chain %s", o.source, c.ChainID())
+	}
+	return o, nil
+}
+
+// GasPrices returns the gas prices used for the last transaction of the chain
+func (c *Chain) GasPrices() sdk.DecCoins {
+	c.gasPrices.mtx.Lock()
+	defer c.gasPrices.mtx.Unlock()
+	return c.gasPrices.used
+}
+
+// current returns the gas prices to be used for the next transaction, querying the source if the cached prices are stale
+func (o *gasPriceOracle) current() (sdk.DecCoins, error) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	if o.query != nil && time.Since(o.refreshedAt) >= gasPriceRefreshInterval {
+		prices, err := o.query()
+		if err != nil {
+			if o.refreshedAt.IsZero() {
+				return nil, fmt.Errorf("failed to query the gas prices of chain %s from %s: %w", o.chainID, o.source, err)
+			}
+			// keep using the last known prices until the source recovers
+			o.logger.Error("failed to query the gas prices", "source", o.source, "error", err)
+		} else {
+			// the source reflects the current congestion, so the raise is reset
+			o.queried, o.raised = prices, nil
+		}
+		o.refreshedAt = time.Now()
+	}
+	// the static source never resets the raise, so it decays after the interval for all the sources
+	if o.raised != nil && time.Since(o.raisedAt) >= gasPriceRefreshInterval {
+		o.raised = nil
+	}
+
+	prices := o.floor
+	if o.query != nil {
+		prices = maxDecCoins(o.floor, o.queried)
+	}
+	prices = capDecCoins(maxDecCoins(prices, o.raised), o.ceiling)
+	if prices.String() != o.used.String() {
+		o.logger.Info("gas prices changed", "from", o.used.String(), "to", prices.String(), "source", o.source)
+		o.used = prices
+	}
+	return prices, nil
+}
+
+// raise raises the gas prices towards the upper limit and returns false if they can't be raised any more
+func (o *gasPriceOracle) raise() bool {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	ratio := sdk.MustNewDecFromStr(gasPriceRaiseRatio)
+	var raised sdk.DecCoins
+	for _, p := range o.used {
+		// only the denoms with an upper limit are raised
+		amount := p.Amount
+		if o.ceiling.AmountOf(p.Denom).IsPositive() {
+			amount = amount.Mul(ratio)
+		}
+		raised = append(raised, sdk.NewDecCoinFromDec(p.Denom, amount))
+	}
+	raised = capDecCoins(sdk.NewDecCoins(raised...), o.ceiling)
+	if raised.String() == o.used.String() {
+		return false
+	}
+	o.raised, o.raisedAt = raised, time.Now()
+	return true
+}
+
+// maxDecCoins returns the larger amount of each denom in `base`, or the coins of `other` if `base` is empty
+func maxDecCoins(base, other sdk.DecCoins) sdk.DecCoins {
+	if base.Empty() {
+		return other
+	}
+	var ret sdk.DecCoins
+	for _, c := range base {
+		amount := c.Amount
+		if o := other.AmountOf(c.Denom); o.GT(amount) {
+			amount = o
+		}
+		ret = append(ret, sdk.NewDecCoinFromDec(c.Denom, amount))
+	}
+	return sdk.NewDecCoins(ret...)
+}
+
+// capDecCoins limits the amount of each denom to the one in `ceiling` if it has the denom
+func capDecCoins(coins, ceiling sdk.DecCoins) sdk.DecCoins {
+	var ret sdk.DecCoins
+	for _, c := range coins {
+		amount := c.Amount
+		if limit := ceiling.AmountOf(c.Denom); limit.IsPositive() && amount.GT(limit) {
+			amount = limit
+		}
+		ret = append(ret, sdk.NewDecCoinFromDec(c.Denom, amount))
+	}
+	return sdk.NewDecCoins(ret...)
+}
+
+// isInsufficientFee returns true if the transaction is rejected because its fee is below the minimum
+func isInsufficientFee(codespace string, code uint32) bool {
+	return codespace == sdkerrors.ErrInsufficientFee.Codespace() && code == sdkerrors.ErrInsufficientFee.ABCICode()
+}
+
+// queryNodeMinGasPrices queries the minimum gas prices configured on the node
+func (c *Chain) queryNodeMinGasPrices() (sdk.DecCoins, error) {
+	res, err := node.NewServiceClient(c.CLIContext(0)).Config(context.Background(), &node.ConfigRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return sdk.ParseDecCoins(res.MinimumGasPrice)
+}
+
+// queryFeeMarketGasPrices queries the current gas prices of the fee market module
+func (c *Chain) queryFeeMarketGasPrices() (sdk.DecCoins, error) {
+	res, err := c.CLIContext(0).QueryABCI(abci.RequestQuery{Path: feeMarketGasPricesPath})
+	if err != nil {
+		return nil, err
+	}
+	// GasPricesResponse has the prices as `repeated cosmos.base.v1beta1.DecCoin prices = 1`
+	var prices sdk.DecCoins
+	bz := res.Value
+	for len(bz) > 0 {
+		num, typ, n := protowire.ConsumeTag(bz)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		if num != 1 || typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, bz); n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			bz = bz[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(bz)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		var price sdk.DecCoin
+		if err := price.Unmarshal(v); err != nil {
+			return nil, fmt.Errorf("failed to decode the gas prices of the fee market: %w", err)
+		}
+		prices = append(prices, price)
+	}
+	return sdk.NewDecCoins(prices...), nil
+}
@@ -0,0 +1,79 @@
+package tendermint
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestGasPriceOracleRaiseDecays(t *testing.T) {
+	cases := []struct {
+		name  string
+		query func() (sdk.DecCoins, error)
+	}{
+		{"static", nil},
+		{"dynamic", func() (sdk.DecCoins, error) { return sdk.ParseDecCoins("0.1stake") }},
+	}
+	for _, c := range cases {
+		o := &gasPriceOracle{
+			source:  c.name,
+			floor:   mustParseDecCoins("0.1stake"),
+			ceiling: mustParseDecCoins("1stake"),
+			query:   c.query,
+			logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		}
+		check := func(want string) {
+			t.Helper()
+			prices, err := o.current()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if prices.String() != want {
+				t.Errorf("%s: got %s, want %s", c.name, prices, want)
+			}
+		}
+
+		check("0.100000000000000000stake")
+		if !o.raise() {
+			t.Fatalf("%s: prices are not raised", c.name)
+		}
+		check("0.150000000000000000stake")
+
+		// the raise falls back to the prices of the source after the refresh interval
+		o.raisedAt = o.raisedAt.Add(-gasPriceRefreshInterval)
+		o.refreshedAt = o.refreshedAt.Add(-gasPriceRefreshInterval)
+		check("0.100000000000000000stake")
+	}
+}
+
+func TestGasPriceOracleRaiseCeiling(t *testing.T) {
+	o := &gasPriceOracle{
+		floor:   mustParseDecCoins("0.8stake"),
+		ceiling: mustParseDecCoins("1stake"),
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	if _, err := o.current(); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []bool{true, false} {
+		if got := o.raise(); got != want {
+			t.Errorf("raise #%d returned %v, want %v", i, got, want)
+		}
+		if _, err := o.current(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if prices := o.used; prices.String() != "1.000000000000000000stake" {
+		t.Errorf("got %s, want the ceiling", prices)
+	}
+}
+
+func mustParseDecCoins(s string) sdk.DecCoins {
+	coins, err := sdk.ParseDecCoins(s)
+	if err != nil {
+		panic(err)
+	}
+	return coins
+}
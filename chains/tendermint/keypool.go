@@ -0,0 +1,142 @@
+package tendermint
+
+import (
+	"fmt"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/gogoproto/proto"
+	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
+)
+
+// signer is an account that signs the transactions of the chain
+type signer struct {
+	key       string
+	address   sdk.AccAddress
+	sequencer accountSequencer
+
+	inFlight int // number of transactions being submitted, guarded by keyPool.mtx
+}
+
+// keyPool hands the signers of the chain to transaction submissions so that they can be broadcast in parallel.
+// The first signer is the primary key, which signs the transactions whose msgs can't be delegated to other keys.
+type keyPool struct {
+	mtx     sync.Mutex
+	signers []*signer
+}
+
+// PoolKeys returns the names of the keys that sign transactions in addition to the primary key
+func (c *Chain) PoolKeys() []string {
+	return c.config.Keys
+}
+
+// keyPool returns the key pool of the chain, initializing it on the first call
+func (c *Chain) keyPool() (*keyPool, error) {
+	c.keysOnce.Do(func() {
+		pool := &keyPool{}
+		for _, name := range append([]string{c.config.Key}, c.config.Keys...) {
+			info, err := c.Keybase.Key(name)
+			if err != nil {
+				c.keysErr = fmt.Errorf("failed to get key %s of chain %s: %w", name, c.ChainID(), err)
+				return
+			}
+			addr, err := info.GetAddress()
+			if err != nil {
+				c.keysErr = err
+				return
+			}
+			pool.signers = append(pool.signers, &signer{key: name, address: addr})
+		}
+		c.keys = pool
+	})
+	return c.keys, c.keysErr
+}
+
+// acquire returns the signer with the fewest transactions being submitted, preferring the primary key.
+// Only the primary key is returned unless `delegatable` is true.
+func (p *keyPool) acquire(delegatable bool) *signer {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	s := p.signers[0]
+	if delegatable {
+		for _, t := range p.signers[1:] {
+			if t.inFlight < s.inFlight {
+				s = t
+			}
+		}
+	}
+	s.inFlight++
+	return s
+}
+
+// release returns the signer to the pool
+func (p *keyPool) release(s *signer) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	s.inFlight--
+}
+
+// acquireSigner returns a signer for the msgs and the msgs to be signed by it.
+// The msgs are delegated to a pool key only if all of them are IBC msgs whose signer is the primary key,
+// in which case copies of them with the signer replaced are returned.
+// The packet msgs of a fee-enabled channel are not delegated because only the primary key is registered as the payee.
+func (c *Chain) acquireSigner(msgs []sdk.Msg) (*keyPool, *signer, []sdk.Msg, error) {
+	pool, err := c.keyPool()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	primary, err := c.bech32Address(pool.signers[0].address)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	delegatable := len(pool.signers) > 1
+	feeEnabled := c.PathEnd.IsFeeEnabled()
+	for _, msg := range msgs {
+		if signer, isPacket := msgSigner(msg); signer == nil || *signer != primary || (isPacket && feeEnabled) {
+			delegatable = false
+			break
+		}
+	}
+	s := pool.acquire(delegatable)
+	if s == pool.signers[0] {
+		return pool, s, msgs, nil
+	}
+	addr, err := c.bech32Address(s.address)
+	if err != nil {
+		pool.release(s)
+		return nil, nil, nil, err
+	}
+	delegated := make([]sdk.Msg, len(msgs))
+	for i, msg := range msgs {
+		delegated[i] = proto.Clone(msg).(sdk.Msg)
+		signer, _ := msgSigner(delegated[i])
+		*signer = addr
+	}
+	return pool, s, delegated, nil
+}
+
+// bech32Address encodes the address with the account prefix of the chain regardless of the global SDK config
+func (c *Chain) bech32Address(addr sdk.AccAddress) (string, error) {
+	return bech32.ConvertAndEncode(c.config.AccountPrefix, addr)
+}
+
+// msgSigner returns the signer field of the msg if the msg can be signed by any key of the pool, or nil otherwise.
+// `isPacket` is true if the msg relays a packet, which may be incentivized by the fee middleware.
+func msgSigner(msg sdk.Msg) (signer *string, isPacket bool) {
+	switch msg := msg.(type) {
+	case *clienttypes.MsgUpdateClient:
+		return &msg.Signer, false
+	case *chantypes.MsgRecvPacket:
+		return &msg.Signer, true
+	case *chantypes.MsgAcknowledgement:
+		return &msg.Signer, true
+	case *chantypes.MsgTimeout:
+		return &msg.Signer, true
+	case *chantypes.MsgTimeoutOnClose:
+		return &msg.Signer, true
+	default:
+		return nil, false
+	}
+}
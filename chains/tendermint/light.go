@@ -8,8 +8,8 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"time"
 
-	"github.com/avast/retry-go"
 	dbm "github.com/cometbft/cometbft-db"
 	"github.com/cometbft/cometbft/libs/log"
 	"github.com/cometbft/cometbft/light"
@@ -54,15 +54,15 @@ func (pr *Prover) LightHTTP() lightp.Provider {
 	return cl
 }
 
-func (pr *Prover) NewLightDB() (db *dbm.GoLevelDB, df func(), err error) {
+func (pr *Prover) NewLightDB(ctx context.Context) (db *dbm.GoLevelDB, df func(), err error) {
 	c := pr.chain
-	if err := retry.Do(func() error {
+	if err := c.retryPolicy.Do(ctx, func() error {
 		db, err = dbm.NewGoLevelDB(c.config.ChainId, lightDir(c.HomePath))
 		if err != nil {
 			return fmt.Errorf("can't open light client database: %w", err)
 		}
 		return nil
-	}, rtyAtt, rtyDel, rtyErr); err != nil {
+	}, nil); err != nil {
 		return nil, nil, err
 	}
 
@@ -83,10 +83,10 @@ func (pr *Prover) DeleteLightDB() error {
 
 // LightClientWithTrust takes a header from the chain and attempts to add that header to the light
 // database.
-func (pr *Prover) LightClientWithTrust(db dbm.DB, to light.TrustOptions) (*light.Client, error) {
+func (pr *Prover) LightClientWithTrust(ctx context.Context, db dbm.DB, to light.TrustOptions) (*light.Client, error) {
 	prov := pr.LightHTTP()
 	return light.NewClient(
-		context.Background(),
+		ctx,
 		pr.chain.config.ChainId,
 		to,
 		prov,
@@ -99,15 +99,15 @@ func (pr *Prover) LightClientWithTrust(db dbm.DB, to light.TrustOptions) (*light
 
 // LightClientWithoutTrust querys the latest header from the chain and initializes a new light client
 // database using that header. This should only be called when first initializing the light client
-func (pr *Prover) LightClientWithoutTrust(db dbm.DB) (*light.Client, error) {
+func (pr *Prover) LightClientWithoutTrust(ctx context.Context, db dbm.DB) (*light.Client, error) {
 	var (
 		height int64
 		err    error
 	)
 	prov := pr.LightHTTP()
 
-	if err := retry.Do(func() error {
-		h, err := pr.chain.LatestHeight()
+	if err := pr.chain.retryPolicy.Do(ctx, func() error {
+		h, err := pr.chain.LatestHeight(ctx)
 		switch {
 		case err != nil:
 			return err
@@ -117,16 +117,16 @@ func (pr *Prover) LightClientWithoutTrust(db dbm.DB) (*light.Client, error) {
 			height = int64(h.GetRevisionHeight())
 			return nil
 		}
-	}, rtyAtt, rtyDel, rtyErr); err != nil {
+	}, nil); err != nil {
 		return nil, err
 	}
 
-	lb, err := prov.LightBlock(context.Background(), height)
+	lb, err := prov.LightBlock(ctx, height)
 	if err != nil {
 		return nil, err
 	}
 	return light.NewClient(
-		context.Background(),
+		ctx,
 		pr.chain.config.ChainId,
 		light.TrustOptions{
 			Period: pr.getTrustingPeriod(),
@@ -142,14 +142,14 @@ func (pr *Prover) LightClientWithoutTrust(db dbm.DB) (*light.Client, error) {
 }
 
 // GetLatestLightHeader returns the header to be used for client creation
-func (pr *Prover) GetLatestLightHeader() (*tmclient.Header, error) {
-	return pr.GetLightSignedHeaderAtHeight(0)
+func (pr *Prover) GetLatestLightHeader(ctx context.Context) (*tmclient.Header, error) {
+	return pr.GetLightSignedHeaderAtHeight(ctx, 0)
 }
 
 // GetLightSignedHeaderAtHeight returns a signed header at a particular height.
-func (pr *Prover) GetLightSignedHeaderAtHeight(height int64) (*tmclient.Header, error) {
+func (pr *Prover) GetLightSignedHeaderAtHeight(ctx context.Context, height int64) (*tmclient.Header, error) {
 	// create database connection
-	db, df, err := pr.NewLightDB()
+	db, df, err := pr.NewLightDB(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -175,6 +175,36 @@ func (pr *Prover) GetLightSignedHeaderAtHeight(height int64) (*tmclient.Header,
 	return &tmclient.Header{SignedHeader: sh.SignedHeader.ToProto(), ValidatorSet: protoVal}, nil
 }
 
+// GetVerifiedLightHeaderAtHeight returns a signed header at a particular height verified by the light client.
+// Unlike GetLightSignedHeaderAtHeight, the header is fetched from the chain if it is not in the trusted store.
+func (pr *Prover) GetVerifiedLightHeaderAtHeight(ctx context.Context, height int64) (*tmclient.Header, error) {
+	// create database connection
+	db, df, err := pr.NewLightDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer df()
+
+	client, err := pr.LightClient(db)
+	if err != nil {
+		return nil, err
+	}
+
+	lb, err := client.VerifyLightBlockAtHeight(ctx, height, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	valSet := tmtypes.NewValidatorSet(lb.ValidatorSet.Validators)
+	protoVal, err := valSet.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	protoVal.TotalVotingPower = valSet.TotalVotingPower()
+
+	return &tmclient.Header{SignedHeader: lb.SignedHeader.ToProto(), ValidatorSet: protoVal}, nil
+}
+
 func lightDir(home string) string {
 	return path.Join(home, "light")
 }
@@ -2,49 +2,69 @@ package tendermint
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	proto "github.com/cosmos/gogoproto/proto"
 	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
 	ibcexported "github.com/cosmos/ibc-go/v7/modules/core/exported"
+	"github.com/hyperledger-labs/yui-relayer/core"
 )
 
+// pathLogger returns the logger with the identifiers of the chain and its path end
+func (c *Chain) pathLogger() *slog.Logger {
+	return core.WithChain(c.logger, c)
+}
+
 // LogFailedTx takes the transaction and the messages to create it and logs the appropriate data
 func (c *Chain) LogFailedTx(res *sdk.TxResponse, err error, msgs []sdk.Msg) {
+	logger := c.pathLogger().With("msgs", getMsgAction(msgs))
 	if c.debug {
-		c.Log(fmt.Sprintf("- [%s] -> sending transaction:", c.ChainID()))
 		for _, msg := range msgs {
-			c.Print(msg, false, false)
+			if bz, err := c.codec.MarshalJSON(msg); err == nil {
+				logger.Debug("failed transaction msg", "msg", string(bz))
+			}
 		}
 	}
 
 	if err != nil {
-		c.logger.Error(fmt.Errorf("- [%s] -> err(%v)", c.ChainID(), err).Error())
+		logger.Error("failed to send transaction", "error", err)
 		if res == nil {
 			return
 		}
 	}
 
 	if res.Code != 0 && res.Codespace != "" {
-		c.logger.Info(fmt.Sprintf("✘ [%s]@{%d} - msg(%s) err(%s:%d:%s)",
-			c.ChainID(), res.Height, getMsgAction(msgs), res.Codespace, res.Code, res.RawLog))
+		logger.Error("transaction failed",
+			"tx_hash", res.TxHash,
+			"height", res.Height,
+			"codespace", res.Codespace,
+			"code", res.Code,
+			"raw_log", res.RawLog,
+		)
 	}
 
 	if c.debug && !res.Empty() {
-		c.Log("- transaction response:")
-		c.Print(res, false, false)
+		if bz, err := c.codec.MarshalJSON(res); err == nil {
+			logger.Debug("failed transaction response", "response", string(bz))
+		}
 	}
 }
 
 // LogSuccessTx take the transaction and the messages to create it and logs the appropriate data
 func (c *Chain) LogSuccessTx(res *sdk.TxResponse, msgs []sdk.Msg) {
-	c.logger.Info(fmt.Sprintf("✔ [%s]@{%d} - msg(%s) hash(%s)", c.ChainID(), res.Height, getMsgAction(msgs), res.TxHash))
+	c.pathLogger().Info("transaction succeeded",
+		"msgs", getMsgAction(msgs),
+		"tx_hash", res.TxHash,
+		"height", res.Height,
+		"gas_used", res.GasUsed,
+	)
 }
 
 // Log takes a string and logs the data
 func (c *Chain) Log(s string) {
-	c.logger.Info(s)
+	c.pathLogger().Info(s)
 }
 
 // Print fmt.Printlns the json or yaml representation of whatever is passed in
@@ -0,0 +1,61 @@
+package tendermint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	tmtypes "github.com/cometbft/cometbft/types"
+	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v7/modules/core/exported"
+	tmclient "github.com/cosmos/ibc-go/v7/modules/light-clients/07-tendermint"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+var _ core.MisbehaviourChecker = (*Prover)(nil)
+
+// CheckMisbehaviour compares a header submitted to the client of this chain on the counterparty chain
+// with the header at the same height verified by the light client. If they conflict, it returns a misbehaviour
+// consisting of the two headers, both of which can be verified with the trusted consensus state of the submitted header.
+func (pr *Prover) CheckMisbehaviour(ctx context.Context, clientID string, header core.Header) (ibcexported.ClientMessage, error) {
+	submitted, ok := header.(*tmclient.Header)
+	if !ok {
+		return nil, fmt.Errorf("unexpected header type: %T", header)
+	}
+	height := submitted.GetHeight().(clienttypes.Height)
+	if height.RevisionNumber != clienttypes.ParseChainID(pr.chain.ChainID()) {
+		return nil, fmt.Errorf("header of a different revision: expected=%d actual=%d", clienttypes.ParseChainID(pr.chain.ChainID()), height.RevisionNumber)
+	}
+
+	verified, err := pr.GetVerifiedLightHeaderAtHeight(ctx, int64(height.RevisionHeight))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the verified header at %v: %w", height, err)
+	}
+	if equal, err := headerHashEqual(verified, submitted); err != nil {
+		return nil, err
+	} else if equal {
+		return nil, nil
+	}
+
+	// the verified header must be verifiable with the same consensus state as the submitted one
+	verified.TrustedHeight = submitted.TrustedHeight
+	valSet, err := pr.chain.QueryValsetAtHeight(submitted.TrustedHeight)
+	if err != nil {
+		return nil, err
+	}
+	verified.TrustedValidators = valSet
+
+	return tmclient.NewMisbehaviour(clientID, verified, submitted), nil
+}
+
+func headerHashEqual(h1, h2 *tmclient.Header) (bool, error) {
+	th1, err := tmtypes.HeaderFromProto(h1.Header)
+	if err != nil {
+		return false, err
+	}
+	th2, err := tmtypes.HeaderFromProto(h2.Header)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(th1.Hash(), th2.Hash()), nil
+}
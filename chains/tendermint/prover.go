@@ -4,18 +4,22 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
+	abci "github.com/cometbft/cometbft/abci/types"
 	"github.com/cometbft/cometbft/light"
 	tmtypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
-	ibcclient "github.com/cosmos/ibc-go/v7/modules/core/client"
+	commitmenttypes "github.com/cosmos/ibc-go/v7/modules/core/23-commitment/types"
 	ibcexported "github.com/cosmos/ibc-go/v7/modules/core/exported"
 	tmclient "github.com/cosmos/ibc-go/v7/modules/light-clients/07-tendermint"
 
 	"github.com/hyperledger-labs/yui-relayer/core"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Prover struct {
@@ -24,12 +28,13 @@ type Prover struct {
 }
 
 var _ core.Prover = (*Prover)(nil)
+var _ core.TrustingPeriodProvider = (*Prover)(nil)
 
 func NewProver(chain *Chain, config ProverConfig) *Prover {
 	return &Prover{chain: chain, config: config}
 }
 
-func (pr *Prover) Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, debug bool) error {
+func (pr *Prover) Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, logger *slog.Logger, debug bool) error {
 	return nil
 }
 
@@ -43,9 +48,11 @@ func (pr *Prover) SetupForRelay(ctx context.Context) error {
 }
 
 // ProveState returns the proof of an IBC state specified by `path` and `value`
-func (pr *Prover) ProveState(ctx core.QueryContext, path string, value []byte) ([]byte, clienttypes.Height, error) {
+func (pr *Prover) ProveState(ctx core.QueryContext, path string, value []byte) (_ []byte, _ clienttypes.Height, err error) {
+	span := core.StartQuerySpan(ctx, "ProveState", pr.chain, attribute.String("path", path))
+	defer func() { core.EndSpan(span, err) }()
 	clientCtx := pr.chain.CLIContext(int64(ctx.Height().GetRevisionHeight()))
-	if v, proof, proofHeight, err := ibcclient.QueryTendermintProof(clientCtx, []byte(path)); err != nil {
+	if v, proof, proofHeight, err := queryTendermintProof(clientCtx, pr.chain.storeKey(), []byte(path)); err != nil {
 		return nil, clienttypes.Height{}, err
 	} else if !bytes.Equal(v, value) {
 		return nil, clienttypes.Height{}, fmt.Errorf("value unmatch: %x != %x", v, value)
@@ -54,6 +61,47 @@ func (pr *Prover) ProveState(ctx core.QueryContext, path string, value []byte) (
 	}
 }
 
+// queryTendermintProof performs an ABCI query of `key` in the store specified by `storeKey`
+// and returns the value, the proto encoded merkle proof and the height of the block containing the state root.
+// It is the same as ibcclient.QueryTendermintProof except that the store is configurable.
+func queryTendermintProof(clientCtx client.Context, storeKey string, key []byte) ([]byte, []byte, clienttypes.Height, error) {
+	height := clientCtx.Height
+
+	// ABCI queries at heights 1, 2 or less than or equal to 0 are not supported.
+	// A height of 0 will query with the lastest state.
+	if height != 0 && height <= 2 {
+		return nil, nil, clienttypes.Height{}, fmt.Errorf("proof queries at height <= 2 are not supported")
+	}
+
+	// Use the IAVL height if a valid tendermint height is passed in.
+	if height != 0 {
+		height--
+	}
+
+	res, err := clientCtx.QueryABCI(abci.RequestQuery{
+		Path:   fmt.Sprintf("store/%s/key", storeKey),
+		Height: height,
+		Data:   key,
+		Prove:  true,
+	})
+	if err != nil {
+		return nil, nil, clienttypes.Height{}, err
+	}
+
+	merkleProof, err := commitmenttypes.ConvertProofs(res.ProofOps)
+	if err != nil {
+		return nil, nil, clienttypes.Height{}, err
+	}
+
+	proofBz, err := codec.NewProtoCodec(clientCtx.InterfaceRegistry).Marshal(&merkleProof)
+	if err != nil {
+		return nil, nil, clienttypes.Height{}, err
+	}
+
+	revision := clienttypes.ParseChainID(clientCtx.ChainID)
+	return res.Value, proofBz, clienttypes.NewHeight(revision, uint64(res.Height)+1), nil
+}
+
 /* LightClient implementation */
 
 // CreateMsgCreateClient creates a CreateClientMsg to this chain
@@ -71,19 +119,22 @@ func (pr *Prover) CreateMsgCreateClient(clientID string, dstHeader core.Header,
 }
 
 // SetupHeadersForUpdate returns the finalized header and any intermediate headers needed to apply it to the client on the counterpaty chain
-func (pr *Prover) SetupHeadersForUpdate(dstChain core.ChainInfoICS02Querier, latestFinalizedHeader core.Header) ([]core.Header, error) {
+func (pr *Prover) SetupHeadersForUpdate(ctx context.Context, dstChain core.ChainInfoICS02Querier, latestFinalizedHeader core.Header) (_ []core.Header, err error) {
+	ctx, span := core.StartChainSpan(ctx, "Prover.SetupHeadersForUpdate", pr.chain,
+		attribute.Int64("height", int64(latestFinalizedHeader.GetHeight().GetRevisionHeight())))
+	defer func() { core.EndSpan(span, err) }()
 	srcChain := pr.chain
 	// make copy of header stored in mop
 	tmp := latestFinalizedHeader.(*tmclient.Header)
 	h := *tmp
 
-	dsth, err := dstChain.LatestHeight()
+	dsth, err := dstChain.LatestHeight(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	// retrieve counterparty client from dst chain
-	counterpartyClientRes, err := dstChain.QueryClientState(core.NewQueryContext(context.TODO(), dsth))
+	counterpartyClientRes, err := dstChain.QueryClientState(core.NewQueryContext(ctx, dsth))
 	if err != nil {
 		return nil, err
 	}
@@ -108,8 +159,10 @@ func (pr *Prover) SetupHeadersForUpdate(dstChain core.ChainInfoICS02Querier, lat
 }
 
 // GetLatestFinalizedHeader returns the latest finalized header
-func (pr *Prover) GetLatestFinalizedHeader() (latestFinalizedHeader core.Header, err error) {
-	h, err := pr.UpdateLightClient()
+func (pr *Prover) GetLatestFinalizedHeader(ctx context.Context) (latestFinalizedHeader core.Header, err error) {
+	ctx, span := core.StartChainSpan(ctx, "Prover.GetLatestFinalizedHeader", pr.chain)
+	defer func() { core.EndSpan(span, err) }()
+	h, err := pr.UpdateLightClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -119,8 +172,8 @@ func (pr *Prover) GetLatestFinalizedHeader() (latestFinalizedHeader core.Header,
 /* Local LightClient implementation */
 
 // GetLatestLightHeight uses the CLI utilities to pull the latest height from a given chain
-func (pr *Prover) GetLatestLightHeight() (int64, error) {
-	db, df, err := pr.NewLightDB()
+func (pr *Prover) GetLatestLightHeight(ctx context.Context) (int64, error) {
+	db, df, err := pr.NewLightDB(ctx)
 	if err != nil {
 		return -1, err
 	}
@@ -134,9 +187,9 @@ func (pr *Prover) GetLatestLightHeight() (int64, error) {
 	return client.LastTrustedHeight()
 }
 
-func (pr *Prover) UpdateLightClient() (core.Header, error) {
+func (pr *Prover) UpdateLightClient(ctx context.Context) (core.Header, error) {
 	// create database connection
-	db, df, err := pr.NewLightDB()
+	db, df, err := pr.NewLightDB(ctx)
 	if err != nil {
 		return nil, lightError(err)
 	}
@@ -147,7 +200,7 @@ func (pr *Prover) UpdateLightClient() (core.Header, error) {
 		return nil, lightError(err)
 	}
 
-	sh, err := client.Update(context.Background(), time.Now())
+	sh, err := client.Update(ctx, time.Now())
 	if err != nil {
 		return nil, lightError(err)
 	}
@@ -181,6 +234,11 @@ func (pr *Prover) TrustOptions(height int64, hash []byte) light.TrustOptions {
 	}
 }
 
+// TrustingPeriod implements core.TrustingPeriodProvider
+func (pr *Prover) TrustingPeriod() time.Duration {
+	return pr.getTrustingPeriod()
+}
+
 /// internal method ///
 
 // getTrustingPeriod returns the trusting period for the chain
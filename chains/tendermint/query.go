@@ -2,6 +2,7 @@ package tendermint
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"sort"
@@ -17,6 +18,7 @@ import (
 	querytypes "github.com/cosmos/cosmos-sdk/types/query"
 	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	feetypes "github.com/cosmos/ibc-go/v7/modules/apps/29-fee/types"
 	transfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
 	clientutils "github.com/cosmos/ibc-go/v7/modules/core/02-client/client/utils"
 	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
@@ -27,10 +29,15 @@ import (
 	committypes "github.com/cosmos/ibc-go/v7/modules/core/23-commitment/types"
 	ibcexported "github.com/cosmos/ibc-go/v7/modules/core/exported"
 	"github.com/hyperledger-labs/yui-relayer/core"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // QueryClientState retrevies the latest consensus state for a client in state at a given height
-func (c *Chain) QueryClientState(ctx core.QueryContext) (*clienttypes.QueryClientStateResponse, error) {
+func (c *Chain) QueryClientState(ctx core.QueryContext) (_ *clienttypes.QueryClientStateResponse, err error) {
+	span := core.StartQuerySpan(ctx, "QueryClientState", c)
+	defer func() { core.EndSpan(span, err) }()
 	return c.queryClientState(int64(ctx.Height().GetRevisionHeight()), false)
 }
 
@@ -55,7 +62,9 @@ var emptyConnRes = conntypes.NewQueryConnectionResponse(
 )
 
 // QueryConnection returns the remote end of a given connection
-func (c *Chain) QueryConnection(ctx core.QueryContext) (*conntypes.QueryConnectionResponse, error) {
+func (c *Chain) QueryConnection(ctx core.QueryContext) (_ *conntypes.QueryConnectionResponse, err error) {
+	span := core.StartQuerySpan(ctx, "QueryConnection", c)
+	defer func() { core.EndSpan(span, err) }()
 	return c.queryConnection(int64(ctx.Height().GetRevisionHeight()), false)
 }
 
@@ -86,6 +95,8 @@ var emptyChannelRes = chantypes.NewQueryChannelResponse(
 
 // QueryChannel returns the channel associated with a channelID
 func (c *Chain) QueryChannel(ctx core.QueryContext) (chanRes *chantypes.QueryChannelResponse, err error) {
+	span := core.StartQuerySpan(ctx, "QueryChannel", c)
+	defer func() { core.EndSpan(span, err) }()
 	return c.queryChannel(int64(ctx.Height().GetRevisionHeight()), false)
 }
 
@@ -99,9 +110,35 @@ func (c *Chain) queryChannel(height int64, prove bool) (chanRes *chantypes.Query
 	return res, nil
 }
 
+// QueryNextSequenceReceive returns the next sequence receive of the channel
+func (c *Chain) QueryNextSequenceReceive(ctx core.QueryContext) (_ *chantypes.QueryNextSequenceReceiveResponse, err error) {
+	span := core.StartQuerySpan(ctx, "QueryNextSequenceReceive", c)
+	defer func() { core.EndSpan(span, err) }()
+	return chanutils.QueryNextSequenceReceive(c.CLIContext(int64(ctx.Height().GetRevisionHeight())), c.PathEnd.PortID, c.PathEnd.ChannelID, false)
+}
+
+// QueryIncentivizedPacket returns the fees escrowed for the packet, or nil if no fees are escrowed
+func (c *Chain) QueryIncentivizedPacket(ctx core.QueryContext, packetID chantypes.PacketId) (_ *feetypes.QueryIncentivizedPacketResponse, err error) {
+	span := core.StartQuerySpan(ctx, "QueryIncentivizedPacket", c, attribute.Int64("sequence", int64(packetID.Sequence)))
+	defer func() { core.EndSpan(span, err) }()
+	height := ctx.Height().GetRevisionHeight()
+	res, err := feetypes.NewQueryClient(c.CLIContext(int64(height))).IncentivizedPacket(ctx.Context(), &feetypes.QueryIncentivizedPacketRequest{
+		PacketId:    packetID,
+		QueryHeight: height,
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 // QueryClientConsensusState retrevies the latest consensus state for a client in state at a given height
 func (c *Chain) QueryClientConsensusState(
-	ctx core.QueryContext, dstClientConsHeight ibcexported.Height) (*clienttypes.QueryConsensusStateResponse, error) {
+	ctx core.QueryContext, dstClientConsHeight ibcexported.Height) (_ *clienttypes.QueryConsensusStateResponse, err error) {
+	span := core.StartQuerySpan(ctx, "QueryClientConsensusState", c, attribute.String("consensus_height", dstClientConsHeight.String()))
+	defer func() { core.EndSpan(span, err) }()
 	return c.queryClientConsensusState(int64(ctx.Height().GetRevisionHeight()), dstClientConsHeight, false)
 }
 
@@ -116,26 +153,28 @@ func (c *Chain) queryClientConsensusState(
 
 // QueryBalance returns the amount of coins in the relayer account
 func (c *Chain) QueryBalance(ctx core.QueryContext, addr sdk.AccAddress) (sdk.Coins, error) {
-	params := bankTypes.NewQueryAllBalancesRequest(addr, &querytypes.PageRequest{
-		Key:        []byte(""),
-		Offset:     0,
-		Limit:      1000,
-		CountTotal: true,
-	})
-
 	queryClient := bankTypes.NewQueryClient(c.CLIContext(0))
 
-	res, err := queryClient.AllBalances(context.Background(), params)
-	if err != nil {
-		return nil, err
+	var balances sdk.Coins
+	var key []byte
+	for {
+		res, err := queryClient.AllBalances(ctx.Context(), bankTypes.NewQueryAllBalancesRequest(addr, &querytypes.PageRequest{
+			Key:   key,
+			Limit: queryPageLimit,
+		}))
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, res.Balances...)
+		if key = res.Pagination.GetNextKey(); len(key) == 0 {
+			return balances, nil
+		}
 	}
-
-	return res.Balances, nil
 }
 
 // QueryDenomTraces returns all the denom traces from a given chain
 func (c *Chain) QueryDenomTraces(ctx core.QueryContext, offset, limit uint64) (*transfertypes.QueryDenomTracesResponse, error) {
-	return transfertypes.NewQueryClient(c.CLIContext(int64(ctx.Height().GetRevisionHeight()))).DenomTraces(context.Background(), &transfertypes.QueryDenomTracesRequest{
+	return transfertypes.NewQueryClient(c.CLIContext(int64(ctx.Height().GetRevisionHeight()))).DenomTraces(ctx.Context(), &transfertypes.QueryDenomTracesRequest{
 		Pagination: &querytypes.PageRequest{
 			Key:        []byte(""),
 			Offset:     offset,
@@ -145,59 +184,94 @@ func (c *Chain) QueryDenomTraces(ctx core.QueryContext, offset, limit uint64) (*
 	})
 }
 
-// queryPacketCommitments returns an array of packet commitments
-func (c *Chain) queryPacketCommitments(
-	ctx core.QueryContext,
-	offset, limit uint64) (comRes *chantypes.QueryPacketCommitmentsResponse, err error) {
+// queryPacketCommitmentSequences returns the sequences of all the packet commitments, following the pagination
+func (c *Chain) queryPacketCommitmentSequences(ctx core.QueryContext) (_ []uint64, err error) {
+	span := core.StartQuerySpan(ctx, "QueryPacketCommitments", c)
+	defer func() { core.EndSpan(span, err) }()
 	qc := chantypes.NewQueryClient(c.CLIContext(int64(ctx.Height().GetRevisionHeight())))
-	return qc.PacketCommitments(context.Background(), &chantypes.QueryPacketCommitmentsRequest{
-		PortId:    c.PathEnd.PortID,
-		ChannelId: c.PathEnd.ChannelID,
-		Pagination: &querytypes.PageRequest{
-			Offset:     offset,
-			Limit:      limit,
-			CountTotal: true,
-		},
-	})
+	var seqs []uint64
+	var key []byte
+	for {
+		res, err := qc.PacketCommitments(ctx.Context(), &chantypes.QueryPacketCommitmentsRequest{
+			PortId:    c.PathEnd.PortID,
+			ChannelId: c.PathEnd.ChannelID,
+			Pagination: &querytypes.PageRequest{
+				Key:   key,
+				Limit: queryPageLimit,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, ps := range res.Commitments {
+			seqs = append(seqs, ps.Sequence)
+		}
+		if key = res.Pagination.GetNextKey(); len(key) == 0 {
+			return seqs, nil
+		}
+	}
 }
 
-// queryPacketAcknowledgementCommitments returns an array of packet acks
-func (c *Chain) queryPacketAcknowledgementCommitments(ctx core.QueryContext, offset, limit uint64) (comRes *chantypes.QueryPacketAcknowledgementsResponse, err error) {
+// queryPacketAcknowledgementSequences returns the sequences of all the packet acks, following the pagination
+func (c *Chain) queryPacketAcknowledgementSequences(ctx core.QueryContext) (_ []uint64, err error) {
+	span := core.StartQuerySpan(ctx, "QueryPacketAcknowledgements", c)
+	defer func() { core.EndSpan(span, err) }()
 	qc := chantypes.NewQueryClient(c.CLIContext(int64(ctx.Height().GetRevisionHeight())))
-	return qc.PacketAcknowledgements(context.Background(), &chantypes.QueryPacketAcknowledgementsRequest{
-		PortId:    c.PathEnd.PortID,
-		ChannelId: c.PathEnd.ChannelID,
-		Pagination: &querytypes.PageRequest{
-			Offset:     offset,
-			Limit:      limit,
-			CountTotal: true,
-		},
-	})
+	var seqs []uint64
+	var key []byte
+	for {
+		res, err := qc.PacketAcknowledgements(ctx.Context(), &chantypes.QueryPacketAcknowledgementsRequest{
+			PortId:    c.PathEnd.PortID,
+			ChannelId: c.PathEnd.ChannelID,
+			Pagination: &querytypes.PageRequest{
+				Key:   key,
+				Limit: queryPageLimit,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, ps := range res.Acknowledgements {
+			seqs = append(seqs, ps.Sequence)
+		}
+		if key = res.Pagination.GetNextKey(); len(key) == 0 {
+			return seqs, nil
+		}
+	}
 }
 
 // QueryUnreceivedPackets returns a list of unrelayed packet commitments
-func (c *Chain) QueryUnreceivedPackets(ctx core.QueryContext, seqs []uint64) ([]uint64, error) {
+func (c *Chain) QueryUnreceivedPackets(ctx core.QueryContext, seqs []uint64) (_ []uint64, err error) {
+	span := core.StartQuerySpan(ctx, "QueryUnreceivedPackets", c, attribute.Int("sequences", len(seqs)))
+	defer func() { core.EndSpan(span, err) }()
 	qc := chantypes.NewQueryClient(c.CLIContext(int64(ctx.Height().GetRevisionHeight())))
-	res, err := qc.UnreceivedPackets(context.Background(), &chantypes.QueryUnreceivedPacketsRequest{
-		PortId:                    c.PathEnd.PortID,
-		ChannelId:                 c.PathEnd.ChannelID,
-		PacketCommitmentSequences: seqs,
+	return queryInChunks(seqs, func(seqs []uint64) ([]uint64, error) {
+		res, err := qc.UnreceivedPackets(ctx.Context(), &chantypes.QueryUnreceivedPacketsRequest{
+			PortId:                    c.PathEnd.PortID,
+			ChannelId:                 c.PathEnd.ChannelID,
+			PacketCommitmentSequences: seqs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return res.Sequences, nil
 	})
-	if err != nil {
-		return nil, err
-	}
-	return res.Sequences, nil
 }
 
 func (c *Chain) QueryUnfinalizedRelayPackets(ctx core.QueryContext, counterparty core.LightClientICS04Querier) (core.PacketInfoList, error) {
-	res, err := c.queryPacketCommitments(ctx, 0, 1000)
+	seqs, err := c.queryPacketCommitmentSequences(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	// find the packets to relay before querying their events, which is far more expensive
+	if seqs, err = core.QueryUnreceivedOnCounterparty(ctx.Context(), counterparty, seqs, counterparty.QueryUnreceivedPackets); err != nil {
+		return nil, err
+	}
+
 	var packets core.PacketInfoList
-	for _, ps := range res.Commitments {
-		packet, height, err := c.querySentPacket(ctx, ps.Sequence)
+	for _, seq := range seqs {
+		packet, height, err := c.querySentPacket(ctx, seq)
 		if err != nil {
 			return nil, err
 		}
@@ -207,50 +281,45 @@ func (c *Chain) QueryUnfinalizedRelayPackets(ctx core.QueryContext, counterparty
 			EventHeight:     height,
 		})
 	}
-
-	var counterpartyCtx core.QueryContext
-	if counterpartyH, err := counterparty.GetLatestFinalizedHeader(); err != nil {
-		return nil, err
-	} else {
-		counterpartyCtx = core.NewQueryContext(context.TODO(), counterpartyH.GetHeight())
-	}
-
-	seqs, err := counterparty.QueryUnreceivedPackets(counterpartyCtx, packets.ExtractSequenceList())
-	if err != nil {
-		return nil, err
-	}
-	packets = packets.Filter(seqs)
-
 	return packets, nil
 }
 
 // QueryUnreceivedAcknowledgements returns a list of unrelayed packet acks
-func (c *Chain) QueryUnreceivedAcknowledgements(ctx core.QueryContext, seqs []uint64) ([]uint64, error) {
+func (c *Chain) QueryUnreceivedAcknowledgements(ctx core.QueryContext, seqs []uint64) (_ []uint64, err error) {
+	span := core.StartQuerySpan(ctx, "QueryUnreceivedAcknowledgements", c, attribute.Int("sequences", len(seqs)))
+	defer func() { core.EndSpan(span, err) }()
 	qc := chantypes.NewQueryClient(c.CLIContext(int64(ctx.Height().GetRevisionHeight())))
-	res, err := qc.UnreceivedAcks(context.Background(), &chantypes.QueryUnreceivedAcksRequest{
-		PortId:             c.PathEnd.PortID,
-		ChannelId:          c.PathEnd.ChannelID,
-		PacketAckSequences: seqs,
+	return queryInChunks(seqs, func(seqs []uint64) ([]uint64, error) {
+		res, err := qc.UnreceivedAcks(ctx.Context(), &chantypes.QueryUnreceivedAcksRequest{
+			PortId:             c.PathEnd.PortID,
+			ChannelId:          c.PathEnd.ChannelID,
+			PacketAckSequences: seqs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return res.Sequences, nil
 	})
-	if err != nil {
-		return nil, err
-	}
-	return res.Sequences, nil
 }
 
 func (c *Chain) QueryUnfinalizedRelayAcknowledgements(ctx core.QueryContext, counterparty core.LightClientICS04Querier) (core.PacketInfoList, error) {
-	res, err := c.queryPacketAcknowledgementCommitments(ctx, 0, 1000)
+	seqs, err := c.queryPacketAcknowledgementSequences(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	// find the acks to relay before querying their events, which is far more expensive
+	if seqs, err = core.QueryUnreceivedOnCounterparty(ctx.Context(), counterparty, seqs, counterparty.QueryUnreceivedAcknowledgements); err != nil {
+		return nil, err
+	}
+
 	var packets core.PacketInfoList
-	for _, ps := range res.Acknowledgements {
-		packet, rpHeight, err := c.queryReceivedPacket(ctx, ps.Sequence)
+	for _, seq := range seqs {
+		packet, rpHeight, err := c.queryReceivedPacket(ctx, seq)
 		if err != nil {
 			return nil, err
 		}
-		ack, _, err := c.queryWrittenAcknowledgement(ctx, ps.Sequence)
+		ack, _, err := c.queryWrittenAcknowledgement(ctx, seq)
 		if err != nil {
 			return nil, err
 		}
@@ -260,26 +329,30 @@ func (c *Chain) QueryUnfinalizedRelayAcknowledgements(ctx core.QueryContext, cou
 			EventHeight:     rpHeight,
 		})
 	}
+	return packets, nil
+}
 
-	var counterpartyCtx core.QueryContext
-	if counterpartyH, err := counterparty.GetLatestFinalizedHeader(); err != nil {
-		return nil, err
-	} else {
-		counterpartyCtx = core.NewQueryContext(context.TODO(), counterpartyH.GetHeight())
-	}
-
-	seqs, err := counterparty.QueryUnreceivedAcknowledgements(counterpartyCtx, packets.ExtractSequenceList())
-	if err != nil {
-		return nil, err
+// queryInChunks calls `query` with the sequences split into chunks of queryPageLimit and concatenates the results,
+// so that a large backlog doesn't exceed the message size limit of a request
+func queryInChunks(seqs []uint64, query func([]uint64) ([]uint64, error)) ([]uint64, error) {
+	var ret []uint64
+	for len(seqs) > 0 {
+		n := min(len(seqs), queryPageLimit)
+		res, err := query(seqs[:n])
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, res...)
+		seqs = seqs[n:]
 	}
-	packets = packets.Filter(seqs)
-
-	return packets, nil
+	return ret, nil
 }
 
 // querySentPacket finds a SendPacket event corresponding to `seq` and returns the packet in it
-func (c *Chain) querySentPacket(ctx core.QueryContext, seq uint64) (*chantypes.Packet, clienttypes.Height, error) {
-	txs, err := c.QueryTxs(int64(ctx.Height().GetRevisionHeight()), 1, 1000, sendPacketQuery(c.Path().ChannelID, int(seq)))
+func (c *Chain) querySentPacket(ctx core.QueryContext, seq uint64) (_ *chantypes.Packet, _ clienttypes.Height, err error) {
+	span := core.StartQuerySpan(ctx, "QuerySentPacket", c, attribute.Int64("sequence", int64(seq)))
+	defer func() { core.EndSpan(span, err) }()
+	txs, _, err := c.QueryTxs(ctx.Context(), int64(ctx.Height().GetRevisionHeight()), 1, 1000, sendPacketQuery(c.Path().ChannelID, int(seq)))
 	switch {
 	case err != nil:
 		return nil, clienttypes.Height{}, err
@@ -303,8 +376,10 @@ func (c *Chain) querySentPacket(ctx core.QueryContext, seq uint64) (*chantypes.P
 }
 
 // queryReceivedPacket finds a RecvPacket event corresponding to `seq` and return the packet in it
-func (c *Chain) queryReceivedPacket(ctx core.QueryContext, seq uint64) (*chantypes.Packet, clienttypes.Height, error) {
-	txs, err := c.QueryTxs(int64(ctx.Height().GetRevisionHeight()), 1, 1000, recvPacketQuery(c.Path().ChannelID, int(seq)))
+func (c *Chain) queryReceivedPacket(ctx core.QueryContext, seq uint64) (_ *chantypes.Packet, _ clienttypes.Height, err error) {
+	span := core.StartQuerySpan(ctx, "QueryReceivedPacket", c, attribute.Int64("sequence", int64(seq)))
+	defer func() { core.EndSpan(span, err) }()
+	txs, _, err := c.QueryTxs(ctx.Context(), int64(ctx.Height().GetRevisionHeight()), 1, 1000, recvPacketQuery(c.Path().ChannelID, int(seq)))
 	switch {
 	case err != nil:
 		return nil, clienttypes.Height{}, err
@@ -329,8 +404,10 @@ func (c *Chain) queryReceivedPacket(ctx core.QueryContext, seq uint64) (*chantyp
 }
 
 // queryWrittenAcknowledgement finds a WriteAcknowledgement event corresponding to `seq` and returns the acknowledgement in it
-func (c *Chain) queryWrittenAcknowledgement(ctx core.QueryContext, seq uint64) ([]byte, clienttypes.Height, error) {
-	txs, err := c.QueryTxs(int64(ctx.Height().GetRevisionHeight()), 1, 1000, writeAckQuery(c.Path().ChannelID, int(seq)))
+func (c *Chain) queryWrittenAcknowledgement(ctx core.QueryContext, seq uint64) (_ []byte, _ clienttypes.Height, err error) {
+	span := core.StartQuerySpan(ctx, "QueryWrittenAcknowledgement", c, attribute.Int64("sequence", int64(seq)))
+	defer func() { core.EndSpan(span, err) }()
+	txs, _, err := c.QueryTxs(ctx.Context(), int64(ctx.Height().GetRevisionHeight()), 1, 1000, writeAckQuery(c.Path().ChannelID, int(seq)))
 	switch {
 	case err != nil:
 		return nil, clienttypes.Height{}, err
@@ -353,25 +430,93 @@ func (c *Chain) queryWrittenAcknowledgement(ctx core.QueryContext, seq uint64) (
 	return ack.Data(), height, nil
 }
 
-// QueryTxs returns an array of transactions given a tag
-func (c *Chain) QueryTxs(height int64, page, limit int, events []string) ([]*ctypes.ResultTx, error) {
+// QueryTxs returns an array of transactions given a tag and the total number of the transactions that match it
+func (c *Chain) QueryTxs(ctx context.Context, height int64, page, limit int, events []string) ([]*ctypes.ResultTx, int, error) {
 	if len(events) == 0 {
-		return nil, errors.New("must declare at least one event to search")
+		return nil, 0, errors.New("must declare at least one event to search")
 	}
 
 	if page <= 0 {
-		return nil, errors.New("page must greater than 0")
+		return nil, 0, errors.New("page must greater than 0")
 	}
 
 	if limit <= 0 {
-		return nil, errors.New("limit must greater than 0")
+		return nil, 0, errors.New("limit must greater than 0")
+	}
+
+	res, err := c.Client.TxSearch(ctx, strings.Join(events, " AND "), true, &page, &limit, "")
+	if err != nil {
+		return nil, 0, err
 	}
+	return res.Txs, res.TotalCount, nil
+}
 
-	res, err := c.Client.TxSearch(context.Background(), strings.Join(events, " AND "), true, &page, &limit, "")
+// QueryClientUpdateHeaders returns the headers submitted to the client in the blocks from `fromHeight` to the height of `ctx`
+func (c *Chain) QueryClientUpdateHeaders(ctx core.QueryContext, fromHeight uint64) ([]*core.ClientUpdate, error) {
+	events := []string{
+		fmt.Sprintf("%s.%s='%s'", clienttypes.EventTypeUpdateClient, clienttypes.AttributeKeyClientID, c.PathEnd.ClientID),
+	}
+	var updates []*core.ClientUpdate
+	err := c.searchTxsInRange(ctx, fromHeight, events, func(tx *ctypes.ResultTx) error {
+		for _, ev := range tx.TxResult.Events {
+			if ev.Type != clienttypes.EventTypeUpdateClient {
+				continue
+			}
+			var clientID, header string
+			for _, attr := range ev.Attributes {
+				switch attr.Key {
+				case clienttypes.AttributeKeyClientID:
+					clientID = attr.Value
+				case clienttypes.AttributeKeyHeader:
+					header = attr.Value
+				}
+			}
+			if clientID != c.PathEnd.ClientID || header == "" {
+				continue
+			}
+			bz, err := hex.DecodeString(header)
+			if err != nil {
+				return fmt.Errorf("failed to decode the header: %w", err)
+			}
+			msg, err := clienttypes.UnmarshalClientMessage(c.codec, bz)
+			if err != nil {
+				return err
+			}
+			if h, ok := msg.(core.Header); ok {
+				updates = append(updates, &core.ClientUpdate{Height: uint64(tx.Height), Header: h})
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	return res.Txs, nil
+	return updates, nil
+}
+
+// searchTxsInRange calls `f` for each transaction that matches `events` in the blocks from `fromHeight` to the height of `ctx`
+func (c *Chain) searchTxsInRange(ctx core.QueryContext, fromHeight uint64, events []string, f func(tx *ctypes.ResultTx) error) (err error) {
+	span := core.StartQuerySpan(ctx, "SearchTxs", c, attribute.Int64("from_height", int64(fromHeight)))
+	defer func() { core.EndSpan(span, err) }()
+	events = append(events,
+		fmt.Sprintf("tx.height>=%d", fromHeight),
+		fmt.Sprintf("tx.height<=%d", ctx.Height().GetRevisionHeight()),
+	)
+	for page := 1; ; page++ {
+		txs, total, err := c.QueryTxs(ctx.Context(), int64(ctx.Height().GetRevisionHeight()), page, queryPageLimit, events)
+		if err != nil {
+			return err
+		}
+		for _, tx := range txs {
+			if err := f(tx); err != nil {
+				return err
+			}
+		}
+		// the node rejects a page beyond the last one, so stop at the last page even if it is full
+		if len(txs) < queryPageLimit || page*queryPageLimit >= total {
+			return nil
+		}
+	}
 }
 
 /////////////////////////////////////
@@ -453,6 +598,9 @@ func (c *Chain) QueryUnbondingPeriod() (time.Duration, error) {
 	return res.Params.UnbondingTime, nil
 }
 
+// queryPageLimit is the number of items fetched per page of paginated queries
+const queryPageLimit = 100
+
 const (
 	spTag = "send_packet"
 	rpTag = "recv_packet"
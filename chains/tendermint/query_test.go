@@ -0,0 +1,108 @@
+package tendermint
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// txSearchClient serves TxSearch from `total` matching transactions, rejecting a page beyond the last one as CometBFT does
+type txSearchClient struct {
+	rpcclient.Client
+	total    int
+	requests int
+}
+
+func (c *txSearchClient) TxSearch(_ context.Context, _ string, _ bool, page, perPage *int, _ string) (*ctypes.ResultTxSearch, error) {
+	c.requests++
+	pages := (c.total + *perPage - 1) / *perPage
+	if pages == 0 {
+		pages = 1
+	}
+	if *page < 1 || *page > pages {
+		return nil, fmt.Errorf("page should be within [1, %d] range, given %d", pages, *page)
+	}
+	var txs []*ctypes.ResultTx
+	for i := (*page - 1) * *perPage; i < c.total && i < *page**perPage; i++ {
+		txs = append(txs, &ctypes.ResultTx{Height: int64(i + 1)})
+	}
+	return &ctypes.ResultTxSearch{Txs: txs, TotalCount: c.total}, nil
+}
+
+func TestSearchTxsInRange(t *testing.T) {
+	cases := []struct {
+		total        int
+		wantRequests int
+	}{
+		{0, 1},
+		{1, 1},
+		{queryPageLimit - 1, 1},
+		{queryPageLimit, 1},
+		{queryPageLimit + 1, 2},
+		{2 * queryPageLimit, 2},
+	}
+	for _, c := range cases {
+		client := &txSearchClient{total: c.total}
+		chain := &Chain{Client: client, PathEnd: &core.PathEnd{}}
+		ctx := core.NewQueryContext(context.TODO(), clienttypes.NewHeight(0, 1000))
+		var found int
+		err := chain.searchTxsInRange(ctx, 1, []string{"update_client.client_id='07-tendermint-0'"}, func(tx *ctypes.ResultTx) error {
+			found++
+			return nil
+		})
+		if err != nil {
+			t.Errorf("%d matches: unexpected error: %v", c.total, err)
+			continue
+		}
+		if found != c.total {
+			t.Errorf("%d matches: found %d transactions", c.total, found)
+		}
+		if client.requests != c.wantRequests {
+			t.Errorf("%d matches: sent %d requests, want %d", c.total, client.requests, c.wantRequests)
+		}
+	}
+}
+
+func TestQueryInChunks(t *testing.T) {
+	for _, n := range []int{0, 1, queryPageLimit, queryPageLimit + 1, 3*queryPageLimit + 7} {
+		var seqs []uint64
+		for i := 1; i <= n; i++ {
+			seqs = append(seqs, uint64(i))
+		}
+		var requests int
+		// pretend that the even sequences are unreceived
+		got, err := queryInChunks(seqs, func(chunk []uint64) ([]uint64, error) {
+			requests++
+			if len(chunk) > queryPageLimit {
+				t.Errorf("%d sequences: a request has %d sequences", n, len(chunk))
+			}
+			var ret []uint64
+			for _, seq := range chunk {
+				if seq%2 == 0 {
+					ret = append(ret, seq)
+				}
+			}
+			return ret, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (n + queryPageLimit - 1) / queryPageLimit; requests != want {
+			t.Errorf("%d sequences: sent %d requests, want %d", n, requests, want)
+		}
+		if len(got) != n/2 {
+			t.Errorf("%d sequences: got %d unreceived sequences, want %d", n, len(got), n/2)
+		}
+		for i, seq := range got {
+			if seq != uint64(2*(i+1)) {
+				t.Errorf("%d sequences: got %d at %d", n, seq, i)
+				break
+			}
+		}
+	}
+}
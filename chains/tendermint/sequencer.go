@@ -0,0 +1,49 @@
+package tendermint
+
+import (
+	"strings"
+	"sync"
+
+	sdkCtx "github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// accountSequencer caches the account number and sequence of the relayer account and increments the sequence
+// locally for each broadcast transaction, so that transactions can be broadcast before the previous ones are committed
+type accountSequencer struct {
+	mtx      sync.Mutex
+	synced   bool
+	number   uint64
+	sequence uint64
+}
+
+// factory returns the tx factory with the cached account number and sequence, querying them from the node if not synced.
+// The caller must hold the lock.
+func (s *accountSequencer) factory(clientCtx sdkCtx.Context, txf tx.Factory) (tx.Factory, error) {
+	if !s.synced {
+		txf, err := prepareFactory(clientCtx, txf)
+		if err != nil {
+			return txf, err
+		}
+		s.number, s.sequence, s.synced = txf.AccountNumber(), txf.Sequence(), true
+	}
+	return txf.WithAccountNumber(s.number).WithSequence(s.sequence), nil
+}
+
+// invalidate makes the sequencer query the account number and sequence from the node on the next broadcast
+func (s *accountSequencer) invalidate() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.synced = false
+}
+
+// isSequenceMismatch returns true if the transaction is rejected because of an unexpected account sequence
+func isSequenceMismatch(codespace string, code uint32) bool {
+	return codespace == sdkerrors.ErrWrongSequence.Codespace() && code == sdkerrors.ErrWrongSequence.ABCICode()
+}
+
+// isSequenceMismatchError returns true if the error is caused by an unexpected account sequence
+func isSequenceMismatchError(err error) bool {
+	return strings.Contains(err.Error(), sdkerrors.ErrWrongSequence.Error())
+}
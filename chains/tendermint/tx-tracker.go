@@ -0,0 +1,119 @@
+package tendermint
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	defaultTxInclusionTimeout = time.Minute
+	txPollInterval            = time.Second
+	maxTxResubmissions        = 2
+)
+
+// waitForInclusion polls the transaction until it is included in a block, the inclusion timeout elapses or `ctx` is done.
+// It returns nil without an error if the transaction has been dropped from the mempool.
+func (c *Chain) waitForInclusion(ctx context.Context, txHash string) (_ *ctypes.ResultTx, err error) {
+	ctx, span := core.StartChainSpan(ctx, "WaitForInclusion", c, attribute.String("tx_hash", txHash))
+	defer func() { core.EndSpan(span, err) }()
+	hash, err := hex.DecodeString(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tx hash %s: %w", txHash, err)
+	}
+	deadline := time.Now().Add(c.txInclusionTimeout)
+	for {
+		if err := sleepContext(ctx, txPollInterval); err != nil {
+			return nil, err
+		}
+		if res, err := c.queryTx(ctx, hash); err != nil || res != nil {
+			return res, err
+		}
+		inMempool, err := c.isInMempool(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if !inMempool {
+			// the transaction may have been committed after the last poll
+			if err := sleepContext(ctx, txPollInterval); err != nil {
+				return nil, err
+			}
+			if res, err := c.queryTx(ctx, hash); err != nil || res != nil {
+				return res, err
+			}
+			return nil, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("transaction %s was not included in %v", txHash, c.txInclusionTimeout)
+		}
+	}
+}
+
+// queryTx returns the transaction with the hash, or nil if it is not included yet
+func (c *Chain) queryTx(ctx context.Context, hash []byte) (*ctypes.ResultTx, error) {
+	res, err := c.Client.Tx(ctx, hash, false)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+// isInMempool returns true if the transaction with the hash is in the mempool of the node
+func (c *Chain) isInMempool(ctx context.Context, hash []byte) (bool, error) {
+	limit := 100
+	res, err := c.Client.UnconfirmedTxs(ctx, &limit)
+	if err != nil {
+		return false, err
+	}
+	// assume that the transaction is still pending if the mempool has more transactions than returned
+	if res.Total > res.Count {
+		return true, nil
+	}
+	for _, tx := range res.Txs {
+		if bytes.Equal(tx.Hash(), hash) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sleepContext waits for `d` and returns the error of `ctx` if it's done before that
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// QueryTxStatus implements core.TxStatusQuerier
+func (c *Chain) QueryTxStatus(ctx context.Context, txHash string) (*core.TxResult, bool, error) {
+	hash, err := hex.DecodeString(txHash)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid tx hash %s: %w", txHash, err)
+	}
+	if resTx, err := c.queryTx(ctx, hash); err != nil {
+		return nil, false, err
+	} else if resTx != nil {
+		return txResult(sdk.NewResponseResultTx(resTx, nil, "")), false, nil
+	}
+	inMempool, err := c.isInMempool(ctx, hash)
+	if err != nil {
+		return nil, false, err
+	}
+	return nil, inMempool, nil
+}
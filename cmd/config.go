@@ -149,6 +149,11 @@ func initConfig(ctx *config.Context, cmd *cobra.Command) error {
 				os.Exit(1)
 			}
 
+			if err := config.InitLogger(ctx, debug); err != nil {
+				fmt.Println("Error initializing logger:", err)
+				os.Exit(1)
+			}
+
 			// ensure config has []*relayer.Chain used for all chain operations
 			err = config.InitChains(ctx, homePath, debug)
 			if err != nil {
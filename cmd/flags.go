@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/hyperledger-labs/yui-relayer/core"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -16,6 +20,9 @@ const (
 	flagTimeoutHeightOffset = "timeout-height-offset"
 	flagTimeoutTimeOffset   = "timeout-time-offset"
 	flagIBCDenoms           = "ibc-denoms"
+	flagFee                 = "fee"
+	flagStrategyOptions     = "strategy-options"
+	flagDryRun              = "dry-run"
 )
 
 func heightFlag(cmd *cobra.Command) *cobra.Command {
@@ -85,3 +92,67 @@ func ibcDenomFlags(cmd *cobra.Command) *cobra.Command {
 	}
 	return cmd
 }
+
+func strategyOptionsFlag(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagStrategyOptions, "", "JSON object that overrides the strategy options of the path (e.g. '{\"max-msg-length\":10}')")
+	return cmd
+}
+
+// getStrategy returns the strategy of the path whose options are overridden by the strategy-options flag
+func getStrategy(cmd *cobra.Command, path *core.Path) (core.StrategyI, error) {
+	overrides, err := cmd.Flags().GetString(flagStrategyOptions)
+	if err != nil {
+		return nil, err
+	}
+	if overrides == "" {
+		return path.GetStrategy()
+	}
+	cfg, err := path.Strategy.WithOptionOverrides(json.RawMessage(overrides))
+	if err != nil {
+		return nil, err
+	}
+	pth := *path
+	pth.Strategy = &cfg
+	return pth.GetStrategy()
+}
+
+func dryRunFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Bool(flagDryRun, false, "build and simulate the transactions and print them instead of broadcasting them")
+	cmd.Flags().Bool(flagYAML, false, "print the transactions of the dry run in yaml instead of json")
+	return cmd
+}
+
+// withDryRun calls `f`, in which the chains record the transactions instead of broadcasting them if the dry-run flag is set.
+// The recorded transactions are printed after `f` returns.
+func withDryRun(cmd *cobra.Command, src, dst *core.ProvableChain, f func() error) error {
+	dryRun, err := cmd.Flags().GetBool(flagDryRun)
+	if err != nil {
+		return err
+	} else if !dryRun {
+		return f()
+	}
+
+	d := core.NewDryRun()
+	src.SetDryRun(d)
+	dst.SetDryRun(d)
+	if err := f(); err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(d.Txs(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if y, _ := cmd.Flags().GetBool(flagYAML); y {
+		// JSON is a subset of YAML
+		var v interface{}
+		if err := yaml.Unmarshal(out, &v); err != nil {
+			return err
+		}
+		if out, err = yaml.Marshal(v); err != nil {
+			return err
+		}
+	}
+	fmt.Println(string(out))
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"sort"
 
 	"github.com/hyperledger-labs/yui-relayer/config"
+	"github.com/hyperledger-labs/yui-relayer/core"
 	"github.com/spf13/cobra"
 )
 
@@ -27,15 +28,45 @@ func showModulesCmd(ctx *config.Context) *cobra.Command {
 		Short: "Shows a list of modules included in the relayer",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			names := make([]string, len(ctx.Modules))
+			providers := make(map[string]string)
 			for i, m := range ctx.Modules {
 				names[i] = m.Name()
+				if sm, ok := m.(config.StrategyModuleI); ok {
+					registry := &strategyRecorder{}
+					if err := sm.RegisterStrategies(registry); err != nil {
+						return err
+					}
+					for _, t := range registry.types {
+						providers[t] = m.Name()
+					}
+				}
 			}
 			sort.Strings(names)
 			for _, name := range names {
 				fmt.Printf("%v\n", name)
 			}
+			fmt.Println("strategies:")
+			for _, t := range core.StrategyTypes() {
+				if provider, ok := providers[t]; ok {
+					fmt.Printf("  %v (%v)\n", t, provider)
+				} else {
+					fmt.Printf("  %v\n", t)
+				}
+			}
 			return nil
 		},
 	}
 	return cmd
 }
+
+// strategyRecorder is a StrategyRegistry that only records the registered strategy types
+type strategyRecorder struct {
+	types []string
+}
+
+var _ core.StrategyRegistry = (*strategyRecorder)(nil)
+
+func (r *strategyRecorder) RegisterStrategy(strategyType string, _ core.StrategyBuilder) error {
+	r.types = append(r.types, strategyType)
+	return nil
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 
@@ -52,12 +51,12 @@ func queryClientCmd(ctx *config.Context) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			latestHeight, err := c.LatestHeight()
+			latestHeight, err := c.LatestHeight(cmd.Context())
 			if err != nil {
 				return err
 			}
 			queryHeight := clienttypes.NewHeight(latestHeight.GetRevisionNumber(), uint64(height))
-			res, err := c.QueryClientState(core.NewQueryContext(context.TODO(), queryHeight))
+			res, err := c.QueryClientState(core.NewQueryContext(cmd.Context(), queryHeight))
 			if err != nil {
 				return err
 			}
@@ -89,12 +88,12 @@ func queryConnection(ctx *config.Context) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			latestHeight, err := c.LatestHeight()
+			latestHeight, err := c.LatestHeight(cmd.Context())
 			if err != nil {
 				return err
 			}
 			queryHeight := clienttypes.NewHeight(latestHeight.GetRevisionNumber(), uint64(height))
-			res, err := c.QueryConnection(core.NewQueryContext(context.TODO(), queryHeight))
+			res, err := c.QueryConnection(core.NewQueryContext(cmd.Context(), queryHeight))
 			if err != nil {
 				return err
 			}
@@ -122,12 +121,12 @@ func queryChannel(ctx *config.Context) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			latestHeight, err := c.LatestHeight()
+			latestHeight, err := c.LatestHeight(cmd.Context())
 			if err != nil {
 				return err
 			}
 			queryHeight := clienttypes.NewHeight(latestHeight.GetRevisionNumber(), uint64(height))
-			res, err := c.QueryChannel(core.NewQueryContext(context.TODO(), queryHeight))
+			res, err := c.QueryChannel(core.NewQueryContext(cmd.Context(), queryHeight))
 			if err != nil {
 				return err
 			}
@@ -160,7 +159,7 @@ func queryBalanceCmd(ctx *config.Context) *cobra.Command {
 				return err
 			}
 
-			h, err := chain.LatestHeight()
+			h, err := chain.LatestHeight(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -191,15 +190,15 @@ func queryUnrelayedPackets(ctx *config.Context) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			sh, err := core.NewSyncHeaders(c[src], c[dst])
+			sh, err := core.NewSyncHeaders(cmd.Context(), c[src], c[dst])
 			if err != nil {
 				return err
 			}
-			st, err := core.GetStrategy(*path.Strategy)
+			st, err := path.GetStrategy()
 			if err != nil {
 				return err
 			}
-			sp, err := st.UnrelayedPackets(c[src], c[dst], sh)
+			sp, err := st.UnrelayedPackets(cmd.Context(), c[src], c[dst], sh)
 			if err != nil {
 				return err
 			}
@@ -239,16 +238,16 @@ func queryUnrelayedAcknowledgements(ctx *config.Context) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			sh, err := core.NewSyncHeaders(c[src], c[dst])
+			sh, err := core.NewSyncHeaders(cmd.Context(), c[src], c[dst])
 			if err != nil {
 				return err
 			}
-			st, err := core.GetStrategy(*path.Strategy)
+			st, err := path.GetStrategy()
 			if err != nil {
 				return err
 			}
 
-			sp, err := st.UnrelayedAcknowledgements(c[src], c[dst], sh)
+			sp, err := st.UnrelayedAcknowledgements(cmd.Context(), c[src], c[dst], sh)
 			if err != nil {
 				return err
 			}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bufio"
+	"fmt"
 	"os"
 	"strings"
 
@@ -48,6 +49,16 @@ func Execute(modules ...config.ModuleI) error {
 	for _, module := range modules {
 		module.RegisterInterfaces(codec.InterfaceRegistry())
 	}
+
+	// Register strategies
+
+	for _, module := range modules {
+		if sm, ok := module.(config.StrategyModuleI); ok {
+			if err := sm.RegisterStrategies(core.DefaultStrategyRegistry()); err != nil {
+				return fmt.Errorf("failed to register strategies of module %s: %w", module.Name(), err)
+			}
+		}
+	}
 	ctx := &config.Context{Modules: modules, Config: &config.Config{}, Codec: codec}
 
 	// Register subcommands
@@ -60,6 +71,7 @@ func Execute(modules ...config.ModuleI) error {
 		queryCmd(ctx),
 		modulesCmd(ctx),
 		serviceCmd(ctx),
+		storeCmd(ctx),
 		flags.LineBreak,
 	)
 	for _, module := range modules {
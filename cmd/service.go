@@ -2,10 +2,16 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
 	"time"
 
 	"github.com/hyperledger-labs/yui-relayer/config"
 	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/hyperledger-labs/yui-relayer/log"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -24,31 +30,158 @@ func serviceCmd(ctx *config.Context) *cobra.Command {
 
 func startCmd(ctx *config.Context) *cobra.Command {
 	const (
-		flagRelayInterval = "relay-interval"
+		flagRelayInterval          = "relay-interval"
+		flagEventDriven            = "event-driven"
+		flagClientRefreshThreshold = "client-refresh-threshold"
+		flagDetectMisbehaviour     = "detect-misbehaviour"
+		flagCheckpoints            = "checkpoints"
+		flagMetricsAddr            = "metrics-addr"
+		flagAdminAddr              = "admin-addr"
+		flagAll                    = "all"
 	)
 
 	cmd := &cobra.Command{
-		Use:  "start [path-name]",
-		Args: cobra.ExactArgs(1),
+		Use:   "start [path-name...]",
+		Short: "Start the relay service for the given paths",
+		Long:  "Start the relay service for the given paths. Chains shared among the paths are connected only once.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if all, _ := cmd.Flags().GetBool(flagAll); all {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
-			if err != nil {
-				return err
+			threshold := viper.GetFloat64(flagClientRefreshThreshold)
+			if threshold < 0 || threshold >= 1 {
+				return fmt.Errorf("%s must be in [0, 1): %v", flagClientRefreshThreshold, threshold)
 			}
-			path, err := ctx.Config.Paths.Get(args[0])
-			if err != nil {
-				return err
+			opts := []core.RelayServiceOption{
+				core.WithClientRefresh(threshold),
+				core.WithRetryPolicy(ctx.Config.GlobalRetryPolicy()),
 			}
-			st, err := core.GetStrategy(*path.Strategy)
+			if viper.GetBool(flagEventDriven) {
+				opts = append(opts, core.WithEventDriven())
+			}
+			if viper.GetBool(flagDetectMisbehaviour) {
+				opts = append(opts, core.WithMisbehaviourDetection())
+			}
+			if viper.GetBool(flagCheckpoints) {
+				store, err := core.OpenCheckpointStore(checkpointStoreDir())
+				if err != nil {
+					return err
+				}
+				defer store.Close()
+				opts = append(opts, core.WithCheckpointStore(store))
+			}
+
+			shutdownTracer, err := config.InitTracer(ctx)
 			if err != nil {
 				return err
 			}
-			if err := st.SetupRelay(context.TODO(), c[src], c[dst]); err != nil {
+			defer shutdownTracer(context.Background())
+
+			// stop the service on SIGINT/SIGTERM after the in-flight broadcasts finish
+			runCtx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			go func() {
+				// a second signal kills the process immediately
+				<-runCtx.Done()
+				stop()
+			}()
+
+			if addr := viper.GetString(flagMetricsAddr); addr != "" {
+				metricsCtx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				if err := core.ServeMetrics(metricsCtx, addr); err != nil {
+					return err
+				}
+			}
+
+			pathNames := args
+			if viper.GetBool(flagAll) {
+				for name := range ctx.Config.Paths {
+					pathNames = append(pathNames, name)
+				}
+				sort.Strings(pathNames)
+			}
+			// the admin API is served after the paths are added to it
+			admin := core.NewAdminServer(ctx.Config.Redacted)
+			adminCtx, cancelAdmin := context.WithCancel(context.Background())
+			defer cancelAdmin()
+			serveAdmin := func() error {
+				if addr := viper.GetString(flagAdminAddr); addr != "" {
+					return admin.Serve(adminCtx, addr)
+				}
+				return nil
+			}
+			if len(pathNames) == 1 {
+				c, src, dst, err := ctx.Config.ChainsFromPath(pathNames[0])
+				if err != nil {
+					return err
+				}
+				path, err := ctx.Config.Paths.Get(pathNames[0])
+				if err != nil {
+					return err
+				}
+				st, err := path.GetStrategy()
+				if err != nil {
+					return err
+				}
+				if err := st.SetupRelay(runCtx, c[src], c[dst]); err != nil {
+					return err
+				}
+				control := core.NewPathControl(pathNames[0], path)
+				admin.AddPath(control)
+				if err := serveAdmin(); err != nil {
+					return err
+				}
+				opts = append(opts, core.WithPathControl(control))
+				return stoppedBySignal(runCtx, core.StartService(runCtx, st, c[src], c[dst], viper.GetDuration(flagRelayInterval), opts...))
+			}
+
+			sv := core.NewSupervisor(viper.GetDuration(flagRelayInterval), opts...)
+			for _, name := range pathNames {
+				c, src, dst, err := ctx.Config.ChainsFromPath(name)
+				if err != nil {
+					return err
+				}
+				path, err := ctx.Config.Paths.Get(name)
+				if err != nil {
+					return err
+				}
+				st, err := path.GetStrategy()
+				if err != nil {
+					return err
+				}
+				if err := sv.AddPath(name, path, c[src], c[dst], st); err != nil {
+					return err
+				}
+			}
+			for _, control := range sv.PathControls() {
+				admin.AddPath(control)
+			}
+			if err := serveAdmin(); err != nil {
 				return err
 			}
-			return core.StartService(context.Background(), st, c[src], c[dst], viper.GetDuration(flagRelayInterval))
+			return stoppedBySignal(runCtx, sv.Start(runCtx))
 		},
 	}
 	cmd.Flags().Duration(flagRelayInterval, 3*time.Second, "time interval to perform relays")
+	cmd.Flags().Bool(flagEventDriven, false, "perform relays when the chains notify relevant events, using relay-interval as a fallback heartbeat")
+	cmd.Flags().Float64(flagClientRefreshThreshold, 2.0/3.0, "fraction of the trusting period after which the clients are updated even if there are no packets to relay (0 to disable)")
+	cmd.Flags().Bool(flagDetectMisbehaviour, false, "check the headers submitted to the clients and submit misbehaviours if they conflict with the chains")
+	cmd.Flags().Bool(flagCheckpoints, false, "persist the relay progress in the checkpoint store under the home directory, which only one process can open at a time")
+	cmd.Flags().Bool(flagAll, false, "relay all the paths in the config")
+	cmd.Flags().String(flagMetricsAddr, "", "address to serve the Prometheus metrics at /metrics (e.g. localhost:9090), disabled if empty")
+	cmd.Flags().String(flagAdminAddr, "", "address to serve the admin API for health checks and controls of the paths (e.g. localhost:9091), disabled if empty")
 	return cmd
 }
+
+// stoppedBySignal returns nil instead of `err` if the service has stopped because the process received a signal
+func stoppedBySignal(ctx context.Context, err error) error {
+	if ctx.Err() == nil {
+		return err
+	}
+	log.GetLogger().Info("relay service stopped by signal")
+	return nil
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/hyperledger-labs/yui-relayer/config"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// checkpointStoreDir returns the directory of the checkpoint store in the home directory
+func checkpointStoreDir() string {
+	return filepath.Join(homePath, "store")
+}
+
+func storeCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "store",
+		Short: "manage the checkpoint store",
+		Long:  "Commands to inspect and reset the checkpoints that the relay service persists with --checkpoints. The store can't be opened while a relay service is using it.",
+	}
+	cmd.AddCommand(
+		storeShowCmd(ctx),
+		storeResetCmd(ctx),
+	)
+	return cmd
+}
+
+func storeShowCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show [path-name]",
+		Short: "show the checkpoints of the path ends of the path, or of all path ends",
+		Args:  cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := core.OpenCheckpointStore(checkpointStoreDir())
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			cps, err := listCheckpoints(ctx, store, args)
+			if err != nil {
+				return err
+			}
+			if viper.GetBool(flagJSON) {
+				bz, err := json.MarshalIndent(cps, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(bz))
+				return nil
+			}
+			for _, key := range sortedKeys(cps) {
+				cp := cps[key]
+				fmt.Printf("%s: packets=%d (scanned up to %d), acks=%d (scanned up to %d), in-flight txs=%d\n",
+					key, len(cp.Packets), cp.PacketsHeight, len(cp.Acks), cp.AcksHeight, len(cp.Txs))
+			}
+			return nil
+		},
+	}
+	return jsonFlag(cmd)
+}
+
+func storeResetCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset [path-name]",
+		Short: "delete the checkpoints of the path ends of the path, or of all path ends",
+		Long:  "Delete the checkpoints so that the relay service rebuilds its state from the chains on the next start.",
+		Args:  cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := core.OpenCheckpointStore(checkpointStoreDir())
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			cps, err := listCheckpoints(ctx, store, args)
+			if err != nil {
+				return err
+			}
+			for _, key := range sortedKeys(cps) {
+				if err := store.Delete(key); err != nil {
+					return err
+				}
+				fmt.Printf("deleted the checkpoint of %s\n", key)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// listCheckpoints returns the checkpoints of the path ends of the path given in `args`, or all checkpoints if no path is given
+func listCheckpoints(ctx *config.Context, store *core.CheckpointStore, args []string) (map[string]*core.Checkpoint, error) {
+	cps, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return cps, nil
+	}
+	path, err := ctx.Config.Paths.Get(args[0])
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]*core.Checkpoint)
+	for _, pe := range []*core.PathEnd{path.Src, path.Dst} {
+		if cp, ok := cps[core.CheckpointKey(pe)]; ok {
+			ret[core.CheckpointKey(pe)] = cp
+		}
+	}
+	return ret, nil
+}
+
+func sortedKeys(cps map[string]*core.Checkpoint) []string {
+	var keys []string
+	for key := range cps {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,7 +1,7 @@
 package cmd
 
 import (
-	"context"
+	"fmt"
 	"strings"
 
 	"github.com/cosmos/cosmos-sdk/client/flags"
@@ -30,6 +30,10 @@ func transactionCmd(ctx *config.Context) *cobra.Command {
 		updateClientsCmd(ctx),
 		createConnectionCmd(ctx),
 		createChannelCmd(ctx),
+		closeChannelCmd(ctx),
+		flags.LineBreak,
+		registerPayeeCmd(ctx),
+		registerCounterpartyPayeeCmd(ctx),
 	)
 
 	return cmd
@@ -56,10 +60,12 @@ func createClientsCmd(ctx *config.Context) *cobra.Command {
 				return err
 			}
 
-			return core.CreateClients(c[src], c[dst])
+			return withDryRun(cmd, c[src], c[dst], func() error {
+				return core.CreateClients(cmd.Context(), c[src], c[dst])
+			})
 		},
 	}
-	return cmd
+	return dryRunFlags(cmd)
 }
 
 func updateClientsCmd(ctx *config.Context) *cobra.Command {
@@ -83,10 +89,12 @@ func updateClientsCmd(ctx *config.Context) *cobra.Command {
 				return err
 			}
 
-			return core.UpdateClients(c[src], c[dst])
+			return withDryRun(cmd, c[src], c[dst], func() error {
+				return core.UpdateClients(cmd.Context(), c[src], c[dst])
+			})
 		},
 	}
-	return cmd
+	return dryRunFlags(cmd)
 }
 
 func createConnectionCmd(ctx *config.Context) *cobra.Command {
@@ -115,11 +123,13 @@ func createConnectionCmd(ctx *config.Context) *cobra.Command {
 				return err
 			}
 
-			return core.CreateConnection(c[src], c[dst], to)
+			return withDryRun(cmd, c[src], c[dst], func() error {
+				return core.CreateConnection(cmd.Context(), c[src], c[dst], to)
+			})
 		},
 	}
 
-	return timeoutFlag(cmd)
+	return dryRunFlags(timeoutFlag(cmd))
 }
 
 func createChannelCmd(ctx *config.Context) *cobra.Command {
@@ -148,11 +158,30 @@ func createChannelCmd(ctx *config.Context) *cobra.Command {
 				return err
 			}
 
-			return core.CreateChannel(c[src], c[dst], false, to)
+			if fee, _ := cmd.Flags().GetBool(flagFee); fee {
+				wrapped := false
+				for _, pe := range []*core.PathEnd{c[src].Path(), c[dst].Path()} {
+					if !pe.IsFeeEnabled() {
+						pe.Version = core.FeeWrappedVersion(pe.Version)
+						wrapped = true
+					}
+				}
+				// the path must keep the wrapped versions to match the channel, so they are saved unless nothing is submitted
+				if dryRun, _ := cmd.Flags().GetBool(flagDryRun); wrapped && !dryRun {
+					if err := overWriteConfig(ctx, cmd); err != nil {
+						return err
+					}
+				}
+			}
+
+			return withDryRun(cmd, c[src], c[dst], func() error {
+				return core.CreateChannel(cmd.Context(), c[src], c[dst], false, to)
+			})
 		},
 	}
 
-	return timeoutFlag(cmd)
+	cmd.Flags().Bool(flagFee, false, "negotiate the ICS-29 fee middleware by wrapping the channel versions of the path")
+	return dryRunFlags(timeoutFlag(cmd))
 }
 
 func relayMsgsCmd(ctx *config.Context) *cobra.Command {
@@ -169,33 +198,30 @@ func relayMsgsCmd(ctx *config.Context) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			sh, err := core.NewSyncHeaders(c[src], c[dst])
+			sh, err := core.NewSyncHeaders(cmd.Context(), c[src], c[dst])
 			if err != nil {
 				return err
 			}
-			st, err := core.GetStrategy(*path.Strategy)
+			st, err := getStrategy(cmd, path)
 			if err != nil {
 				return err
 			}
 
-			if err := st.SetupRelay(context.TODO(), c[src], c[dst]); err != nil {
+			if err := st.SetupRelay(cmd.Context(), c[src], c[dst]); err != nil {
 				return err
 			}
 
-			sp, err := st.UnrelayedPackets(c[src], c[dst], sh)
+			sp, err := st.UnrelayedPackets(cmd.Context(), c[src], c[dst], sh)
 			if err != nil {
 				return err
 			}
 
-			if err = st.RelayPackets(c[src], c[dst], sp, sh); err != nil {
-				return err
-			}
-
-			return nil
+			return withDryRun(cmd, c[src], c[dst], func() error {
+				return st.RelayPackets(cmd.Context(), c[src], c[dst], sp, sh)
+			})
 		},
 	}
-	// TODO add option support for strategy
-	return cmd
+	return dryRunFlags(strategyOptionsFlag(cmd))
 }
 
 func relayAcksCmd(ctx *config.Context) *cobra.Command {
@@ -213,29 +239,110 @@ func relayAcksCmd(ctx *config.Context) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			sh, err := core.NewSyncHeaders(c[src], c[dst])
+			sh, err := core.NewSyncHeaders(cmd.Context(), c[src], c[dst])
 			if err != nil {
 				return err
 			}
-			st, err := core.GetStrategy(*path.Strategy)
+			st, err := getStrategy(cmd, path)
 			if err != nil {
 				return err
 			}
 
 			// sp.Src contains all sequences acked on SRC but acknowledgement not processed on DST
 			// sp.Dst contains all sequences acked on DST but acknowledgement not processed on SRC
-			sp, err := st.UnrelayedAcknowledgements(c[src], c[dst], sh)
+			sp, err := st.UnrelayedAcknowledgements(cmd.Context(), c[src], c[dst], sh)
+			if err != nil {
+				return err
+			}
+
+			return withDryRun(cmd, c[src], c[dst], func() error {
+				return st.RelayAcknowledgements(cmd.Context(), c[src], c[dst], sp, sh)
+			})
+		},
+	}
+
+	return dryRunFlags(strategyOptionsFlag(cmd))
+}
+
+func closeChannelCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "channel-close [path-name]",
+		Short: "close a channel between two configured chains with a configured path",
+		Long: strings.TrimSpace(`This command closes the channel of the path by sending 'chanCloseInit' to the source chain 
+		and 'chanCloseConfirm' to the destination chain. It resumes the handshake if it has already been started on either end`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+
+			to, err := getTimeout(cmd)
 			if err != nil {
 				return err
 			}
 
-			if err = st.RelayAcknowledgements(c[src], c[dst], sp, sh); err != nil {
+			// ensure that keys exist
+			if _, err = c[src].GetAddress(); err != nil {
+				return err
+			}
+			if _, err = c[dst].GetAddress(); err != nil {
 				return err
 			}
 
-			return nil
+			return core.CloseChannel(cmd.Context(), c[src], c[dst], to)
 		},
 	}
 
+	return timeoutFlag(cmd)
+}
+
+func registerPayeeCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "register-payee [path-name] [chain-id] [payee]",
+		Short: "register the payee address of the relayer for the channel on the given chain of a path",
+		Long: strings.TrimSpace(`This command registers the address to which the ICS-29 timeout and acknowledgement fees 
+		are paid when the relayer relays packets on the channel of the given chain`),
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			switch args[1] {
+			case src:
+				return core.RegisterPayee(cmd.Context(), c[src], c[dst], args[2])
+			case dst:
+				return core.RegisterPayee(cmd.Context(), c[dst], c[src], args[2])
+			default:
+				return fmt.Errorf("chain %s is not on path %s", args[1], args[0])
+			}
+		},
+	}
+	return cmd
+}
+
+func registerCounterpartyPayeeCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "register-counterparty-payee [path-name] [chain-id] [counterparty-payee]",
+		Short: "register the counterparty payee address of the relayer for the channel on the given chain of a path",
+		Long: strings.TrimSpace(`This command registers the address on the counterparty chain to which the ICS-29 receive fees 
+		are paid when the relayer relays packets on the channel of the given chain`),
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			switch args[1] {
+			case src:
+				return core.RegisterCounterpartyPayee(cmd.Context(), c[src], c[dst], args[2])
+			case dst:
+				return core.RegisterCounterpartyPayee(cmd.Context(), c[dst], c[src], args[2])
+			default:
+				return fmt.Errorf("chain %s is not on path %s", args[1], args[0])
+			}
+		},
+	}
 	return cmd
 }
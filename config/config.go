@@ -1,11 +1,14 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/hyperledger-labs/yui-relayer/tracing"
 )
 
 type Config struct {
@@ -27,8 +30,25 @@ func DefaultConfig() Config {
 
 // GlobalConfig describes any global relayer settings
 type GlobalConfig struct {
-	Timeout        string `yaml:"timeout" json:"timeout"`
-	LightCacheSize int    `yaml:"light-cache-size" json:"light-cache-size"`
+	Timeout        string            `yaml:"timeout" json:"timeout"`
+	LightCacheSize int               `yaml:"light-cache-size" json:"light-cache-size"`
+	RetryPolicy    *core.RetryPolicy `yaml:"retry-policy,omitempty" json:"retry-policy,omitempty"`
+	Logger         LoggerConfig      `yaml:"logger" json:"logger"`
+	Tracing        TracingConfig     `yaml:"tracing,omitempty" json:"tracing,omitempty"`
+}
+
+// LoggerConfig describes the format and the destination of the logs
+type LoggerConfig struct {
+	Level  string `yaml:"level" json:"level"`   // "debug", "info", "warn" or "error"
+	Format string `yaml:"format" json:"format"` // "text" or "json"
+	Output string `yaml:"output" json:"output"` // "stdout", "stderr" or a file path
+}
+
+// TracingConfig describes the export of the OpenTelemetry traces of the relay service. Tracing is disabled if Exporter is empty.
+type TracingConfig struct {
+	Exporter string `yaml:"exporter,omitempty" json:"exporter,omitempty"` // "otlp", "stdout" or "file"
+	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"` // the OTLP/gRPC endpoint (e.g. localhost:4317) or the file path
+	Insecure bool   `yaml:"insecure,omitempty" json:"insecure,omitempty"` // disables TLS of the OTLP connection
 }
 
 // newDefaultGlobalConfig returns a global config with defaults set
@@ -36,6 +56,12 @@ func newDefaultGlobalConfig() GlobalConfig {
 	return GlobalConfig{
 		Timeout:        "10s",
 		LightCacheSize: 20,
+		RetryPolicy:    core.DefaultRetryPolicy(),
+		Logger: LoggerConfig{
+			Level:  "info",
+			Format: "text",
+			Output: "stderr",
+		},
 	}
 }
 
@@ -104,6 +130,28 @@ func (c *Config) ChainsFromPath(path string) (map[string]*core.ProvableChain, st
 	return chains, src, dst, nil
 }
 
+// GlobalRetryPolicy returns the global retry policy with the defaults set to the fields that aren't configured
+func (c *Config) GlobalRetryPolicy() *core.RetryPolicy {
+	return core.DefaultRetryPolicy().Merge(c.Global.RetryPolicy)
+}
+
+// InitLogger configures the logger of the relayer with the global config. The level is set to debug if `debug` is true.
+func InitLogger(ctx *Context, debug bool) error {
+	cfg := ctx.Config.Global.Logger
+	level := cfg.Level
+	if debug {
+		level = "debug"
+	}
+	return log.InitLogger(level, cfg.Format, cfg.Output)
+}
+
+// InitTracer sets up the export of the traces with the global config.
+// The returned function flushes the pending spans and must be called before the process exits.
+func InitTracer(ctx *Context) (func(context.Context) error, error) {
+	cfg := ctx.Config.Global.Tracing
+	return tracing.InitTracer(context.Background(), cfg.Exporter, cfg.Endpoint, cfg.Insecure)
+}
+
 // Called to initialize the relayer.Chain types on Config
 func InitChains(ctx *Context, homePath string, debug bool) error {
 	to, err := time.ParseDuration(ctx.Config.Global.Timeout)
@@ -111,8 +159,13 @@ func InitChains(ctx *Context, homePath string, debug bool) error {
 		return fmt.Errorf("did you remember to run 'rly config init' error:%w", err)
 	}
 
-	for _, chain := range ctx.Config.chains {
-		if err := chain.Init(homePath, to, ctx.Codec, debug); err != nil {
+	for i, chain := range ctx.Config.chains {
+		policy := ctx.Config.GlobalRetryPolicy().Merge(ctx.Config.Chains[i].RetryPolicy)
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("invalid retry policy for chain %s: %w", chain.ChainID(), err)
+		}
+		chain.SetRetryPolicy(policy)
+		if err := chain.Init(homePath, to, ctx.Codec, log.GetLogger(), debug); err != nil {
 			return fmt.Errorf("did you remember to run 'rly config init' error:%w", err)
 		}
 	}
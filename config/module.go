@@ -2,6 +2,7 @@ package config
 
 import (
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
 	"github.com/spf13/cobra"
 )
 
@@ -16,3 +17,9 @@ type ModuleI interface {
 	// GetCmd returns the command
 	GetCmd(ctx *Context) *cobra.Command
 }
+
+// StrategyModuleI is an optional interface of Module that provides relay strategies
+type StrategyModuleI interface {
+	// RegisterStrategies registers the strategies of the module to the registry
+	RegisterStrategies(registry core.StrategyRegistry) error
+}
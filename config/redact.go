@@ -0,0 +1,74 @@
+package config
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+)
+
+const redacted = "REDACTED"
+
+// secretKeyPattern matches the names of the config fields and URL query parameters that hold secrets
+var secretKeyPattern = regexp.MustCompile(`(?i)(secret|password|passphrase|token|mnemonic|private|api[-_]?key|authorization)`)
+
+// Redacted returns the config as a JSON object in which the secrets are replaced with "REDACTED".
+// The values of the fields whose names look secret, the passwords in URLs and the secret query parameters of URLs are redacted.
+func (c *Config) Redacted() (interface{}, error) {
+	bz, err := MarshalJSON(*c)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(bz, &v); err != nil {
+		return nil, err
+	}
+	return redact(v), nil
+}
+
+func redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if _, ok := e.(string); ok && secretKeyPattern.MatchString(k) {
+				v[k] = redacted
+			} else {
+				v[k] = redact(e)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = redact(e)
+		}
+		return v
+	case string:
+		return redactURL(v)
+	default:
+		return v
+	}
+}
+
+// redactURL redacts the password and the secret query parameters if `s` is a URL
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return s
+	}
+	changed := false
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+		changed = true
+	}
+	q := u.Query()
+	for k := range q {
+		if secretKeyPattern.MatchString(k) {
+			q.Set(k, redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return s
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,405 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/yui-relayer/log"
+)
+
+// PathControl holds the runtime status of the relay service of a path and lets operators control the service.
+// It outlives the restarts of the service.
+type PathControl struct {
+	name string
+	path *Path
+
+	mtx           sync.Mutex
+	srv           *RelayService // the running service, or nil while it's stopped
+	paused        bool
+	updateClients bool
+	status        PathRelayStatus
+	trigger       chan struct{}
+	// clients are the deadlines of the clients of the path observed by the service, keyed by the chain hosting the client
+	clients map[string]clientDeadline
+}
+
+// clientDeadline is when a client should be refreshed by and when it expires
+type clientDeadline struct {
+	clientID  string
+	refreshBy time.Time
+	expiresAt time.Time
+}
+
+// PathRelayStatus is the runtime status of the relay service of a path
+type PathRelayStatus struct {
+	Name              string      `json:"name"`
+	Src               *PathEnd    `json:"src"`
+	Dst               *PathEnd    `json:"dst"`
+	Running           bool        `json:"running"`
+	Paused            bool        `json:"paused"`
+	LastRelayAt       *time.Time  `json:"last-relay-at,omitempty"`
+	LastRelayDuration string      `json:"last-relay-duration,omitempty"`
+	LastSuccessAt     *time.Time  `json:"last-success-at,omitempty"`
+	LastError         string      `json:"last-error,omitempty"`
+	Backlog           PathBacklog `json:"backlog"`
+}
+
+// PathBacklog is the number of packets and acknowledgements waiting to be relayed as of the last relay
+type PathBacklog struct {
+	SrcPackets int `json:"src-packets"` // sent from src and not received on dst yet
+	DstPackets int `json:"dst-packets"` // sent from dst and not received on src yet
+	SrcAcks    int `json:"src-acks"`    // written on src and not relayed to dst yet
+	DstAcks    int `json:"dst-acks"`    // written on dst and not relayed to src yet
+}
+
+// NewPathControl returns a new control of the relay service of the path
+func NewPathControl(name string, path *Path) *PathControl {
+	return &PathControl{
+		name:    name,
+		path:    path,
+		trigger: make(chan struct{}, 1),
+	}
+}
+
+// WithPathControl makes the service report its status to the control and follow the requests of operators via it
+func WithPathControl(control *PathControl) RelayServiceOption {
+	return func(srv *RelayService) {
+		srv.control = control
+	}
+}
+
+// Name returns the name of the path
+func (c *PathControl) Name() string {
+	return c.name
+}
+
+// Status returns the current status of the relay service
+func (c *PathControl) Status() PathRelayStatus {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	status := c.status
+	status.Name = c.name
+	status.Src, status.Dst = c.path.Src, c.path.Dst
+	status.Running = c.srv != nil
+	status.Paused = c.paused
+	return status
+}
+
+// Pause makes the service skip relays until Resume is called
+func (c *PathControl) Pause() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.paused = true
+}
+
+// Resume resumes the relays of the service and performs one immediately
+func (c *PathControl) Resume() {
+	c.mtx.Lock()
+	c.paused = false
+	c.mtx.Unlock()
+	c.RelayNow()
+}
+
+// RelayNow makes the service perform a relay without waiting for the relay interval
+func (c *PathControl) RelayNow() {
+	select {
+	case c.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// UpdateClientsNow makes the service update both clients in the next relay, which is performed immediately
+func (c *PathControl) UpdateClientsNow() {
+	c.mtx.Lock()
+	c.updateClients = true
+	c.mtx.Unlock()
+	c.RelayNow()
+}
+
+// CheckReady returns an error unless the service is running, both chains are reachable and the clients
+// have been refreshed in time as of the last check of the service.
+// It doesn't wait for the relay in progress because the clients are checked by the service.
+func (c *PathControl) CheckReady(ctx context.Context) error {
+	c.mtx.Lock()
+	srv := c.srv
+	clients := make(map[string]clientDeadline, len(c.clients))
+	for chainID, d := range c.clients {
+		clients[chainID] = d
+	}
+	c.mtx.Unlock()
+	if srv == nil {
+		return errors.New("the relay service is not running")
+	}
+	ctx, cancel := context.WithTimeout(ctx, readyTimeout)
+	defer cancel()
+	return srv.checkReady(ctx, clients)
+}
+
+// the methods below are called by the service and do nothing if the control is nil
+
+func (c *PathControl) attach(srv *RelayService) {
+	if c == nil {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.srv = srv
+}
+
+func (c *PathControl) detach() {
+	c.attach(nil)
+}
+
+func (c *PathControl) isPaused() bool {
+	if c == nil {
+		return false
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.paused
+}
+
+// triggered returns the channel that receives a value when an operator requests a relay. It's nil if the control is nil.
+func (c *PathControl) triggered() <-chan struct{} {
+	if c == nil {
+		return nil
+	}
+	return c.trigger
+}
+
+// takeClientUpdate returns true if an operator has requested a client update since the last call
+func (c *PathControl) takeClientUpdate() bool {
+	if c == nil {
+		return false
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	ok := c.updateClients
+	c.updateClients = false
+	return ok
+}
+
+func (c *PathControl) observeServe(startedAt time.Time, err error) {
+	if c == nil {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.status.LastRelayAt = &startedAt
+	c.status.LastRelayDuration = time.Since(startedAt).String()
+	if err != nil {
+		c.status.LastError = err.Error()
+	} else {
+		c.status.LastSuccessAt = &startedAt
+		c.status.LastError = ""
+	}
+}
+
+// observeClientDeadline records the deadlines of the client on `chain`
+func (c *PathControl) observeClientDeadline(chain Chain, refreshBy, expiresAt time.Time) {
+	if c == nil {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.clients == nil {
+		c.clients = make(map[string]clientDeadline)
+	}
+	c.clients[chain.ChainID()] = clientDeadline{clientID: chain.Path().ClientID, refreshBy: refreshBy, expiresAt: expiresAt}
+}
+
+func (c *PathControl) observeBacklog(packets, acks *RelayPackets) {
+	if c == nil {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if packets != nil {
+		c.status.Backlog.SrcPackets, c.status.Backlog.DstPackets = packets.Backlog()
+	}
+	if acks != nil {
+		c.status.Backlog.SrcAcks, c.status.Backlog.DstAcks = acks.Backlog()
+	}
+}
+
+// readyTimeout is the timeout of the queries of a readiness check
+const readyTimeout = 10 * time.Second
+
+// checkReady checks that both chains are reachable and none of the clients has passed its deadlines.
+// The latest heights don't depend on the path, so they are queried without binding the path to the chains.
+func (srv *RelayService) checkReady(ctx context.Context, clients map[string]clientDeadline) error {
+	for _, chain := range []*ProvableChain{srv.src, srv.dst} {
+		if _, err := chain.LatestHeight(ctx); err != nil {
+			return fmt.Errorf("chain %s is unreachable: %w", chain.ChainID(), err)
+		}
+	}
+	now := time.Now()
+	for _, chain := range []*ProvableChain{srv.src, srv.dst} {
+		d, ok := clients[chain.ChainID()]
+		if !ok {
+			continue
+		}
+		if now.After(d.expiresAt) {
+			return fmt.Errorf("client %s on chain %s has expired", d.clientID, chain.ChainID())
+		} else if now.After(d.refreshBy) {
+			return fmt.Errorf("client %s on chain %s should have been refreshed by %s and expires at %s",
+				d.clientID, chain.ChainID(), d.refreshBy.Format(time.RFC3339), d.expiresAt.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// AdminServer serves the HTTP API to check the health of the relay services and to control them
+type AdminServer struct {
+	controls map[string]*PathControl
+	config   func() (interface{}, error)
+}
+
+// NewAdminServer returns a new admin server. `config` returns the effective config of the relayer with the secrets redacted.
+func NewAdminServer(config func() (interface{}, error)) *AdminServer {
+	return &AdminServer{
+		controls: make(map[string]*PathControl),
+		config:   config,
+	}
+}
+
+// AddPath adds the control of the relay service of a path to the server
+func (s *AdminServer) AddPath(control *PathControl) {
+	s.controls[control.Name()] = control
+}
+
+// Handler returns the HTTP handler of the admin API:
+//
+//	GET  /healthz                       the process is alive
+//	GET  /readyz                        all the paths are ready (see PathControl.CheckReady)
+//	GET  /config                        the effective config with the secrets redacted
+//	GET  /paths                         the status of all the paths
+//	GET  /paths/{name}                  the status of the path
+//	POST /paths/{name}/pause            pause the relays of the path
+//	POST /paths/{name}/resume           resume the relays of the path
+//	POST /paths/{name}/relay            relay the path now
+//	POST /paths/{name}/update-clients   update both clients of the path now
+func (s *AdminServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("/readyz", s.handleReady)
+	mux.HandleFunc("/config", func(w http.ResponseWriter, r *http.Request) {
+		cfg, err := s.config()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, cfg)
+	})
+	mux.HandleFunc("/paths", func(w http.ResponseWriter, r *http.Request) {
+		var statuses []PathRelayStatus
+		for _, name := range s.pathNames() {
+			statuses = append(statuses, s.controls[name].Status())
+		}
+		writeJSON(w, http.StatusOK, statuses)
+	})
+	mux.HandleFunc("/paths/", s.handlePath)
+	mux.Handle("/metrics", MetricsHandler())
+	return mux
+}
+
+func (s *AdminServer) pathNames() []string {
+	var names []string
+	for name := range s.controls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *AdminServer) handleReady(w http.ResponseWriter, r *http.Request) {
+	type pathReadiness struct {
+		Ready bool   `json:"ready"`
+		Error string `json:"error,omitempty"`
+	}
+	code := http.StatusOK
+	ret := make(map[string]pathReadiness)
+	for _, name := range s.pathNames() {
+		if err := s.controls[name].CheckReady(r.Context()); err != nil {
+			code = http.StatusServiceUnavailable
+			ret[name] = pathReadiness{Error: err.Error()}
+		} else {
+			ret[name] = pathReadiness{Ready: true}
+		}
+	}
+	writeJSON(w, code, ret)
+}
+
+func (s *AdminServer) handlePath(w http.ResponseWriter, r *http.Request) {
+	name, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/paths/"), "/")
+	control, ok := s.controls[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("path %s is not relayed", name))
+		return
+	}
+	if action == "" {
+		writeJSON(w, http.StatusOK, control.Status())
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s must be requested with POST", r.URL.Path))
+		return
+	}
+	switch action {
+	case "pause":
+		control.Pause()
+	case "resume":
+		control.Resume()
+	case "relay":
+		control.RelayNow()
+	case "update-clients":
+		control.UpdateClientsNow()
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown action: %s", action))
+		return
+	}
+	log.GetLogger().Info("admin request accepted", "path", name, "action", action)
+	writeJSON(w, http.StatusOK, control.Status())
+}
+
+// Serve starts serving the admin API and the metrics on `addr` in the background until `ctx` is done.
+// It returns an error if it fails to listen on `addr`.
+func (s *AdminServer) Serve(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s for the admin API: %w", addr, err)
+	}
+	server := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	go func() {
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.GetLogger().Error("admin server stopped", "error", err)
+		}
+	}()
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.GetLogger().Error("failed to write the response of the admin API", "error", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
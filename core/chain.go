@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"log/slog"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/codec"
@@ -10,6 +11,7 @@ import (
 	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
 	conntypes "github.com/cosmos/ibc-go/v7/modules/core/03-connection/types"
 	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v7/modules/core/23-commitment/types"
 	ibcexported "github.com/cosmos/ibc-go/v7/modules/core/exported"
 )
 
@@ -17,6 +19,9 @@ import (
 type ProvableChain struct {
 	Chain
 	Prover
+
+	retryPolicy *RetryPolicy
+	dryRun      *DryRun // the transactions are recorded to it instead of being broadcast if set
 }
 
 // NewProvableChain returns a new ProvableChain instance
@@ -24,11 +29,11 @@ func NewProvableChain(chain Chain, prover Prover) *ProvableChain {
 	return &ProvableChain{Chain: chain, Prover: prover}
 }
 
-func (pc *ProvableChain) Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, debug bool) error {
-	if err := pc.Chain.Init(homePath, timeout, codec, debug); err != nil {
+func (pc *ProvableChain) Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, logger *slog.Logger, debug bool) error {
+	if err := pc.Chain.Init(homePath, timeout, codec, logger, debug); err != nil {
 		return err
 	}
-	if err := pc.Prover.Init(homePath, timeout, codec, debug); err != nil {
+	if err := pc.Prover.Init(homePath, timeout, codec, logger, debug); err != nil {
 		return err
 	}
 	return nil
@@ -44,6 +49,25 @@ func (pc *ProvableChain) SetRelayInfo(path *PathEnd, counterparty *ProvableChain
 	return nil
 }
 
+// SetRetryPolicy sets the retry policy of the chain, and also sets it to the Chain and the Prover if they implement RetryPolicySetter
+func (pc *ProvableChain) SetRetryPolicy(policy *RetryPolicy) {
+	pc.retryPolicy = policy
+	if s, ok := pc.Chain.(RetryPolicySetter); ok {
+		s.SetRetryPolicy(policy)
+	}
+	if s, ok := pc.Prover.(RetryPolicySetter); ok {
+		s.SetRetryPolicy(policy)
+	}
+}
+
+// RetryPolicy returns the retry policy of the chain
+func (pc *ProvableChain) RetryPolicy() *RetryPolicy {
+	if pc.retryPolicy == nil {
+		return DefaultRetryPolicy()
+	}
+	return pc.retryPolicy
+}
+
 func (pc *ProvableChain) SetupForRelay(ctx context.Context) error {
 	if err := pc.Chain.SetupForRelay(ctx); err != nil {
 		return err
@@ -66,7 +90,7 @@ type Chain interface {
 	Path() *PathEnd
 
 	// Init initializes the chain
-	Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, debug bool) error
+	Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, logger *slog.Logger, debug bool) error
 
 	// SetRelayInfo sets source's path and counterparty's info to the chain
 	SetRelayInfo(path *PathEnd, counterparty *ProvableChain, counterpartyPath *PathEnd) error
@@ -75,11 +99,12 @@ type Chain interface {
 	SetupForRelay(ctx context.Context) error
 
 	// SendMsgs sends msgs to the chain
-	SendMsgs(msgs []sdk.Msg) ([]byte, error)
+	SendMsgs(ctx context.Context, msgs []sdk.Msg) ([]byte, error)
 
-	// Send sends msgs to the chain and logging a result of it
-	// It returns a boolean value whether the result is success
-	Send(msgs []sdk.Msg) bool
+	// Send sends msgs to the chain, waits for the transaction to be included in a block and logs the result of it.
+	// It returns an error if the transaction is not included or fails. The result is returned whenever the transaction is included,
+	// and also when it is rejected by the node, in which case its height is zero.
+	Send(ctx context.Context, msgs []sdk.Msg) (*TxResult, error)
 
 	// RegisterMsgEventListener registers a given EventListener to the chain
 	RegisterMsgEventListener(MsgEventListener)
@@ -89,6 +114,44 @@ type Chain interface {
 	ICS20Querier
 }
 
+// TxResult is the result of a transaction included in a block or rejected by the node
+type TxResult struct {
+	TxHash    string    `json:"tx_hash"`
+	Height    int64     `json:"height"`
+	Codespace string    `json:"codespace,omitempty"`
+	Code      uint32    `json:"code"`
+	Log       string    `json:"log,omitempty"`
+	GasUsed   int64     `json:"gas_used,omitempty"`
+	Fee       sdk.Coins `json:"fee,omitempty"`
+}
+
+// Success returns true if the transaction is included in a block and executed successfully
+func (r *TxResult) Success() bool {
+	return r != nil && r.Height > 0 && r.Code == 0
+}
+
+// RelayEventSubscriber is an optional interface of Chain that notifies the relay service of events relevant to the path end
+type RelayEventSubscriber interface {
+	// SubscribeRelayEvents subscribes to events of the chain that require the relay service to run.
+	// The returned channel receives a value whenever the relay should be performed until `ctx` is done.
+	// It may be closed if the subscription can't be continued, after which the service relays at its interval.
+	SubscribeRelayEvents(ctx context.Context) (<-chan struct{}, error)
+}
+
+// CommitmentPrefixer is an optional interface of Chain that provides the commitment prefix of its IBC store
+type CommitmentPrefixer interface {
+	// CommitmentPrefix returns the prefix under which the IBC states of the chain are committed
+	CommitmentPrefix() commitmenttypes.MerklePrefix
+}
+
+// GetCommitmentPrefix returns the commitment prefix of the chain, or DefaultCommitmentPrefix if the chain doesn't provide one
+func GetCommitmentPrefix(chain *ProvableChain) commitmenttypes.MerklePrefix {
+	if cp, ok := chain.Chain.(CommitmentPrefixer); ok {
+		return cp.CommitmentPrefix()
+	}
+	return DefaultCommitmentPrefix
+}
+
 // ChainInfo is an interface to the chain's general information
 type ChainInfo interface {
 	// ChainID returns ID of the chain
@@ -98,7 +161,10 @@ type ChainInfo interface {
 	//
 	// NOTE: The returned height does not have to be finalized.
 	// If a finalized height/header is required, the `Prover`'s `GetLatestFinalizedHeader` function should be called instead.
-	LatestHeight() (ibcexported.Height, error)
+	LatestHeight(ctx context.Context) (ibcexported.Height, error)
+
+	// Timestamp returns the block timestamp at the given height
+	Timestamp(ctx context.Context, height ibcexported.Height) (time.Time, error)
 }
 
 // MsgEventListener is a listener that listens a msg send to the chain
@@ -135,6 +201,9 @@ type ICS04Querier interface {
 	// QueryChannel returns the channel associated with a channelID
 	QueryChannel(ctx QueryContext) (chanRes *chantypes.QueryChannelResponse, err error)
 
+	// QueryNextSequenceReceive returns the next sequence receive of the channel
+	QueryNextSequenceReceive(ctx QueryContext) (*chantypes.QueryNextSequenceReceiveResponse, error)
+
 	// QueryUnreceivedPackets returns a list of unrelayed packet commitments
 	QueryUnreceivedPackets(ctx QueryContext, seqs []uint64) ([]uint64, error)
 
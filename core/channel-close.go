@@ -0,0 +1,143 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
+)
+
+// CloseChannel runs the channel closing messages on timeout until they pass.
+// The closing handshake is initiated on src if the channel is open on both ends.
+func CloseChannel(ctx context.Context, src, dst *ProvableChain, to time.Duration) error {
+	ticker := time.NewTicker(to)
+	defer ticker.Stop()
+	var failures uint
+	for ; true; <-ticker.C {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		sh, err := NewSyncHeaders(ctx, src, dst)
+		if err != nil {
+			return err
+		}
+		closeSteps, err := closeChannelStep(ctx, sh, src, dst)
+		if err != nil {
+			return err
+		}
+
+		if !closeSteps.Ready() {
+			break
+		}
+
+		closeSteps.Send(ctx, src, dst)
+		// the next steps can't be built in a dry run because they depend on the result of this one
+		if src.IsDryRun() {
+			return nil
+		}
+
+		switch {
+		// In the case of success and this being the last transaction
+		// debug logging, log closed channel and break
+		case closeSteps.Success() && closeSteps.Last:
+			GetChainPairLogger(src, dst).Info("channel closed")
+			return nil
+		// In the case of success, reset the failures counter
+		case closeSteps.Success():
+			failures = 0
+			continue
+		// In the case of failure, increment the failures counter and exit if the attempts of the retry policy are exhausted
+		case !closeSteps.Success():
+			failures++
+			if failures >= src.RetryPolicy().GetAttempts() {
+				return fmt.Errorf("! Channel close failed: [%s]chan{%s}port{%s} -> [%s]chan{%s}port{%s}",
+					src.ChainID(), src.Path().ChannelID, src.Path().PortID,
+					dst.ChainID(), dst.Path().ChannelID, dst.Path().PortID)
+			}
+			GetChainPairLogger(src, dst).Info("retrying channel close handshake", "failures", failures)
+			if err := sleepContext(ctx, src.RetryPolicy().Backoff(failures)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// closeChannelStep returns the next msgs of the closing handshake, updating the clients with the headers synced by `sh`
+func closeChannelStep(ctx context.Context, sh SyncHeaders, src, dst *ProvableChain) (*RelayMsgs, error) {
+	out := NewRelayMsgs()
+	if err := validatePaths(src, dst); err != nil {
+		return nil, err
+	}
+
+	srcUpdateHeaders, dstUpdateHeaders, err := setupBothHeadersForUpdate(ctx, sh, src, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	srcChan, dstChan, err := QueryChannelPair(sh.GetQueryContext(ctx, src.ChainID()), sh.GetQueryContext(ctx, dst.ChainID()), src, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	// Closing handshake has completed on both ends
+	case srcChan.Channel.State == chantypes.CLOSED && dstChan.Channel.State == chantypes.CLOSED:
+		logChannelStates(src, dst, srcChan, dstChan)
+	// Closing handshake hasn't been started, relay `chanCloseInit` to src
+	case srcChan.Channel.State == chantypes.OPEN && dstChan.Channel.State == chantypes.OPEN:
+		logChannelStates(src, dst, srcChan, dstChan)
+		out.Src = append(out.Src, src.Path().ChanCloseInit(mustGetAddress(src)))
+	// Closing handshake has started on dst, relay `chanCloseConfirm` and `updateClient` to src
+	case srcChan.Channel.State != chantypes.CLOSED && dstChan.Channel.State == chantypes.CLOSED:
+		logChannelStates(src, dst, srcChan, dstChan)
+		addr := mustGetAddress(src)
+		if len(dstUpdateHeaders) > 0 {
+			out.Src = append(out.Src, src.Path().UpdateClients(dstUpdateHeaders, addr)...)
+		}
+		out.Src = append(out.Src, src.Path().ChanCloseConfirm(dstChan, addr))
+		out.Last = true
+	// Closing handshake has started on src, relay `chanCloseConfirm` and `updateClient` to dst
+	case srcChan.Channel.State == chantypes.CLOSED && dstChan.Channel.State != chantypes.CLOSED:
+		logChannelStates(dst, src, dstChan, srcChan)
+		addr := mustGetAddress(dst)
+		if len(srcUpdateHeaders) > 0 {
+			out.Dst = append(out.Dst, dst.Path().UpdateClients(srcUpdateHeaders, addr)...)
+		}
+		out.Dst = append(out.Dst, dst.Path().ChanCloseConfirm(srcChan, addr))
+		out.Last = true
+	default:
+		return nil, fmt.Errorf("channel can't be closed in the states: %v <=> %v", srcChan.Channel.State, dstChan.Channel.State)
+	}
+	return out, nil
+}
+
+// finishChannelClose relays `chanCloseConfirm` if the channel has been closed on only one end
+func (srv *RelayService) finishChannelClose(ctx context.Context) error {
+	srcChan, err := srv.src.QueryChannel(srv.sh.GetQueryContext(ctx, srv.src.ChainID()))
+	if err != nil {
+		return err
+	}
+	dstChan, err := srv.dst.QueryChannel(srv.sh.GetQueryContext(ctx, srv.dst.ChainID()))
+	if err != nil {
+		return err
+	}
+	if (srcChan.Channel.State == chantypes.CLOSED) == (dstChan.Channel.State == chantypes.CLOSED) {
+		return nil
+	}
+
+	closeSteps, err := closeChannelStep(ctx, srv.sh, srv.src, srv.dst)
+	if err != nil {
+		return err
+	}
+	if !closeSteps.Ready() {
+		return nil
+	}
+	if closeSteps.Send(ctx, srv.src, srv.dst); !closeSteps.Success() {
+		return fmt.Errorf("failed to confirm the channel close")
+	}
+	GetChainPairLogger(srv.src, srv.dst).Info("channel closed")
+	return nil
+}
@@ -1,17 +1,17 @@
 package core
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
-	retry "github.com/avast/retry-go"
 	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
 )
 
 // CreateChannel runs the channel creation messages on timeout until they pass
 // TODO: add max retries or something to this function
-func CreateChannel(src, dst *ProvableChain, ordered bool, to time.Duration) error {
+func CreateChannel(ctx context.Context, src, dst *ProvableChain, ordered bool, to time.Duration) error {
 	var order chantypes.Order
 	if ordered {
 		order = chantypes.ORDERED
@@ -20,9 +20,13 @@ func CreateChannel(src, dst *ProvableChain, ordered bool, to time.Duration) erro
 	}
 
 	ticker := time.NewTicker(to)
-	failures := 0
+	defer ticker.Stop()
+	var failures uint
 	for ; true; <-ticker.C {
-		chanSteps, err := createChannelStep(src, dst, order)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		chanSteps, err := createChannelStep(ctx, src, dst, order)
 		if err != nil {
 			return err
 		}
@@ -31,43 +35,47 @@ func CreateChannel(src, dst *ProvableChain, ordered bool, to time.Duration) erro
 			break
 		}
 
-		chanSteps.Send(src, dst)
+		chanSteps.Send(ctx, src, dst)
+		// the next steps can't be built in a dry run because they depend on the result of this one
+		if src.IsDryRun() {
+			return nil
+		}
 
 		switch {
 		// In the case of success and this being the last transaction
 		// debug logging, log created connection and break
 		case chanSteps.Success() && chanSteps.Last:
-			log.Printf("★ Channel created: [%s]chan{%s}port{%s} -> [%s]chan{%s}port{%s}",
-				src.ChainID(), src.Path().ChannelID, src.Path().PortID,
-				dst.ChainID(), dst.Path().ChannelID, dst.Path().PortID)
+			GetChainPairLogger(src, dst).Info("channel created")
 			return nil
 		// In the case of success, reset the failures counter
 		case chanSteps.Success():
 			failures = 0
 			continue
-		// In the case of failure, increment the failures counter and exit if this is the 3rd failure
+		// In the case of failure, increment the failures counter and exit if the attempts of the retry policy are exhausted
 		case !chanSteps.Success():
 			failures++
-			log.Printf("retrying transaction...")
-			time.Sleep(5 * time.Second)
-			if failures > 2 {
+			if failures >= src.RetryPolicy().GetAttempts() {
 				return fmt.Errorf("! Channel failed: [%s]chan{%s}port{%s} -> [%s]chan{%s}port{%s}",
 					src.ChainID(), src.Path().ClientID, src.Path().ChannelID,
 					dst.ChainID(), dst.Path().ClientID, dst.Path().ChannelID)
 			}
+			GetChainPairLogger(src, dst).Info("retrying channel handshake", "failures", failures)
+			if err := sleepContext(ctx, src.RetryPolicy().Backoff(failures)); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
-func createChannelStep(src, dst *ProvableChain, ordering chantypes.Order) (*RelayMsgs, error) {
+func createChannelStep(ctx context.Context, src, dst *ProvableChain, ordering chantypes.Order) (*RelayMsgs, error) {
 	out := NewRelayMsgs()
 	if err := validatePaths(src, dst); err != nil {
 		return nil, err
 	}
 	// First, update the light clients to the latest header and return the header
-	sh, err := NewSyncHeaders(src, dst)
+	sh, err := NewSyncHeaders(ctx, src, dst)
 	if err != nil {
 		return nil, err
 	}
@@ -77,20 +85,20 @@ func createChannelStep(src, dst *ProvableChain, ordering chantypes.Order) (*Rela
 		srcUpdateHeaders, dstUpdateHeaders []Header
 	)
 
-	err = retry.Do(func() error {
-		srcUpdateHeaders, dstUpdateHeaders, err = sh.SetupBothHeadersForUpdate(src, dst)
+	err = src.RetryPolicy().Do(ctx, func() error {
+		srcUpdateHeaders, dstUpdateHeaders, err = sh.SetupBothHeadersForUpdate(ctx, src, dst)
 		return err
-	}, rtyAtt, rtyDel, rtyErr, retry.OnRetry(func(n uint, err error) {
+	}, func(n uint, err error) {
 		// logRetryUpdateHeaders(src, dst, n, err)
-		if err := sh.Updates(src, dst); err != nil {
+		if err := sh.Updates(ctx, src, dst); err != nil {
 			panic(err)
 		}
-	}))
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	srcChan, dstChan, err := QueryChannelPair(sh.GetQueryContext(src.ChainID()), sh.GetQueryContext(dst.ChainID()), src, dst)
+	srcChan, dstChan, err := QueryChannelPair(sh.GetQueryContext(ctx, src.ChainID()), sh.GetQueryContext(ctx, dst.ChainID()), src, dst)
 	if err != nil {
 		return nil, err
 	}
@@ -164,14 +172,8 @@ func createChannelStep(src, dst *ProvableChain, ordering chantypes.Order) (*Rela
 }
 
 func logChannelStates(src, dst Chain, srcChan, dstChan *chantypes.QueryChannelResponse) {
-	log.Printf("- [%s]@{%d}chan(%s)-{%s} : [%s]@{%d}chan(%s)-{%s}",
-		src.ChainID(),
-		mustGetHeight(srcChan.ProofHeight),
-		src.Path().ChannelID,
-		srcChan.Channel.State,
-		dst.ChainID(),
-		mustGetHeight(dstChan.ProofHeight),
-		dst.Path().ChannelID,
-		dstChan.Channel.State,
+	GetChainPairLogger(src, dst).Info("channel states",
+		slog.Group("src", "height", mustGetHeight(srcChan.ProofHeight), "state", srcChan.Channel.State.String()),
+		slog.Group("dst", "height", mustGetHeight(dstChan.ProofHeight), "state", dstChan.Channel.State.String()),
 	)
 }
@@ -0,0 +1,486 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	dbm "github.com/cometbft/cometbft-db"
+)
+
+// Checkpoint is the relay progress of a path end that is persisted across restarts
+type Checkpoint struct {
+	// Packets are the packets sent from the path end whose commitments are known to exist, keyed by sequence.
+	// The packet is nil if it is pending but hasn't been queried yet.
+	Packets map[uint64]*PacketInfo `json:"packets,omitempty"`
+	// PacketsHeight is the last height scanned for packets sent from the path end
+	PacketsHeight uint64 `json:"packets_height,omitempty"`
+	// Acks are the packets received by the path end whose acknowledgements are known to exist, keyed by sequence.
+	// The packet is nil if it is pending but hasn't been queried yet.
+	Acks map[uint64]*PacketInfo `json:"acks,omitempty"`
+	// AcksHeight is the last height scanned for acknowledgements written on the path end
+	AcksHeight uint64 `json:"acks_height,omitempty"`
+	// Txs are the relay transactions submitted to the path end that aren't confirmed to be included in a block, keyed by hash
+	Txs map[string]*InFlightTx `json:"txs,omitempty"`
+}
+
+func newCheckpoint() *Checkpoint {
+	return &Checkpoint{
+		Packets: make(map[uint64]*PacketInfo),
+		Acks:    make(map[uint64]*PacketInfo),
+		Txs:     make(map[string]*InFlightTx),
+	}
+}
+
+// CheckpointQuerier is an optional interface of Chain that queries the relay packets incrementally from a checkpoint
+type CheckpointQuerier interface {
+	// QueryPacketCommitmentSequences returns the sequences of the packet commitments on the path end
+	QueryPacketCommitmentSequences(ctx QueryContext) ([]uint64, error)
+	// QuerySentPackets returns the packets sent from the path end in the blocks from `fromHeight` to the height of `ctx`
+	QuerySentPackets(ctx QueryContext, fromHeight uint64) (PacketInfoList, error)
+	// QuerySentPacket returns the packet sent from the path end with the sequence
+	QuerySentPacket(ctx QueryContext, seq uint64) (*PacketInfo, error)
+
+	// QueryPacketAcknowledgementSequences returns the sequences of the packet acknowledgement commitments on the path end
+	QueryPacketAcknowledgementSequences(ctx QueryContext) ([]uint64, error)
+	// QueryWrittenAcknowledgements returns the packets whose acknowledgements are written on the path end
+	// in the blocks from `fromHeight` to the height of `ctx`
+	QueryWrittenAcknowledgements(ctx QueryContext, fromHeight uint64) (PacketInfoList, error)
+	// QueryWrittenAcknowledgement returns the packet received by the path end with the sequence and its acknowledgement
+	QueryWrittenAcknowledgement(ctx QueryContext, seq uint64) (*PacketInfo, error)
+}
+
+// CheckpointStrategy is an optional interface of StrategyI that persists its relay progress in a checkpoint store
+type CheckpointStrategy interface {
+	SetCheckpointStore(store *CheckpointStore)
+}
+
+// CheckpointStore is a persistent store of the checkpoints of path ends backed by goleveldb
+type CheckpointStore struct {
+	db dbm.DB
+}
+
+// OpenCheckpointStore opens the checkpoint store in `dir`, creating it if it doesn't exist
+func OpenCheckpointStore(dir string) (*CheckpointStore, error) {
+	db, err := dbm.NewGoLevelDB("checkpoints", dir)
+	if errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EWOULDBLOCK) {
+		return nil, fmt.Errorf("the checkpoint store in %s is locked by another process, e.g. a running relay service: %w", dir, err)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open the checkpoint store in %s: %w", dir, err)
+	}
+	return &CheckpointStore{db: db}, nil
+}
+
+// Close closes the store
+func (s *CheckpointStore) Close() error {
+	return s.db.Close()
+}
+
+// CheckpointKey returns the key of the checkpoint of the path end
+func CheckpointKey(pe *PathEnd) string {
+	return fmt.Sprintf("%s/%s/%s", pe.ChainID, pe.PortID, pe.ChannelID)
+}
+
+// the fields of a checkpoint are stored under its key so that a relay only writes what it has changed,
+// e.g. "<chain>/<port>/<channel>/packets/<sequence>"
+const (
+	checkpointPackets       = "packets"
+	checkpointPacketsHeight = "packets_height"
+	checkpointAcks          = "acks"
+	checkpointAcksHeight    = "acks_height"
+	checkpointTxs           = "txs"
+)
+
+func checkpointFieldKey(key string, fields ...string) []byte {
+	return []byte(strings.Join(append([]string{key}, fields...), "/"))
+}
+
+// sequenceKey formats the sequence so that the keys are ordered by sequence
+func sequenceKey(seq uint64) string {
+	return fmt.Sprintf("%020d", seq)
+}
+
+// prefixEnd returns the end of the iteration over the keys with the prefix
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	end[len(end)-1]++
+	return end
+}
+
+// Load returns the checkpoint of the path end, or an empty one if it hasn't been saved
+func (s *CheckpointStore) Load(pe *PathEnd) (*Checkpoint, error) {
+	cps, err := s.load(checkpointFieldKey(CheckpointKey(pe), ""))
+	if err != nil {
+		return nil, err
+	}
+	if cp, ok := cps[CheckpointKey(pe)]; ok {
+		return cp, nil
+	}
+	return newCheckpoint(), nil
+}
+
+// List returns all the checkpoints in the store keyed by CheckpointKey
+func (s *CheckpointStore) List() (map[string]*Checkpoint, error) {
+	return s.load(nil)
+}
+
+// load returns the checkpoints in the keys with the prefix
+func (s *CheckpointStore) load(prefix []byte) (map[string]*Checkpoint, error) {
+	var end []byte
+	if len(prefix) > 0 {
+		end = prefixEnd(prefix)
+	}
+	it, err := s.db.Iterator(prefix, end)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	ret := make(map[string]*Checkpoint)
+	for ; it.Valid(); it.Next() {
+		// <chain>/<port>/<channel>/<field>[/<id>]
+		parts := strings.SplitN(string(it.Key()), "/", 5)
+		if len(parts) < 4 {
+			// the checkpoints written in a single value by the former versions are rebuilt from the chains
+			continue
+		}
+		key := strings.Join(parts[:3], "/")
+		cp, ok := ret[key]
+		if !ok {
+			cp = newCheckpoint()
+			ret[key] = cp
+		}
+		var id string
+		if len(parts) == 5 {
+			id = parts[4]
+		}
+		if err := cp.decodeField(parts[3], id, it.Value()); err != nil {
+			return nil, fmt.Errorf("failed to decode the checkpoint %s: %w", it.Key(), err)
+		}
+	}
+	return ret, it.Error()
+}
+
+func (cp *Checkpoint) decodeField(field, id string, value []byte) error {
+	switch field {
+	case checkpointPackets, checkpointAcks:
+		seq, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return err
+		}
+		var p *PacketInfo
+		if err := json.Unmarshal(value, &p); err != nil {
+			return err
+		}
+		if field == checkpointPackets {
+			cp.Packets[seq] = p
+		} else {
+			cp.Acks[seq] = p
+		}
+	case checkpointPacketsHeight:
+		return json.Unmarshal(value, &cp.PacketsHeight)
+	case checkpointAcksHeight:
+		return json.Unmarshal(value, &cp.AcksHeight)
+	case checkpointTxs:
+		var tx InFlightTx
+		if err := json.Unmarshal(value, &tx); err != nil {
+			return err
+		}
+		cp.Txs[id] = &tx
+	}
+	return nil
+}
+
+// savePackets writes the packets in `field` of the checkpoint of the path end that differ from `prev`, and the scanned height.
+// The writes aren't synced because a lost update only makes the relay query the packets again.
+func (s *CheckpointStore) savePackets(pe *PathEnd, field, heightField string, prev, packets map[uint64]*PacketInfo, height uint64) error {
+	key := CheckpointKey(pe)
+	b := s.db.NewBatch()
+	defer b.Close()
+	for seq, p := range packets {
+		if q, ok := prev[seq]; ok && q == p {
+			continue
+		}
+		bz, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		if err := b.Set(checkpointFieldKey(key, field, sequenceKey(seq)), bz); err != nil {
+			return err
+		}
+	}
+	for seq := range prev {
+		if _, ok := packets[seq]; !ok {
+			if err := b.Delete(checkpointFieldKey(key, field, sequenceKey(seq))); err != nil {
+				return err
+			}
+		}
+	}
+	bz, err := json.Marshal(height)
+	if err != nil {
+		return err
+	}
+	if err := b.Set(checkpointFieldKey(key, heightField), bz); err != nil {
+		return err
+	}
+	return b.Write()
+}
+
+// loadTxs returns the in-flight transactions submitted to the path end keyed by hash
+func (s *CheckpointStore) loadTxs(pe *PathEnd) (map[string]*InFlightTx, error) {
+	cps, err := s.load(checkpointFieldKey(CheckpointKey(pe), checkpointTxs, ""))
+	if err != nil {
+		return nil, err
+	}
+	if cp, ok := cps[CheckpointKey(pe)]; ok {
+		return cp.Txs, nil
+	}
+	return nil, nil
+}
+
+// saveTx writes the in-flight transaction submitted to the path end
+func (s *CheckpointStore) saveTx(pe *PathEnd, tx *InFlightTx) error {
+	bz, err := json.Marshal(tx)
+	if err != nil {
+		return err
+	}
+	return s.db.SetSync(checkpointFieldKey(CheckpointKey(pe), checkpointTxs, tx.TxHash), bz)
+}
+
+// deleteTx deletes the in-flight transaction submitted to the path end
+func (s *CheckpointStore) deleteTx(pe *PathEnd, txHash string) error {
+	return s.db.DeleteSync(checkpointFieldKey(CheckpointKey(pe), checkpointTxs, txHash))
+}
+
+// Delete deletes the checkpoint with the key
+func (s *CheckpointStore) Delete(key string) error {
+	prefix := checkpointFieldKey(key, "")
+	it, err := s.db.Iterator(prefix, prefixEnd(prefix))
+	if err != nil {
+		return err
+	}
+	var keys [][]byte
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, append([]byte{}, it.Key()...))
+	}
+	if err := it.Error(); err != nil {
+		it.Close()
+		return err
+	}
+	it.Close()
+
+	b := s.db.NewBatch()
+	defer b.Close()
+	// the checkpoint may have been written in a single value by the former versions
+	if err := b.Delete([]byte(key)); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return b.WriteSync()
+}
+
+// scanCheckpointPackets adds the packets in the blocks after `scannedHeight` up to the height of `ctx` to `known`
+// and returns the new scanned height. Nothing is scanned if `scannedHeight` is zero because the checkpoint is built from the commitments then.
+func scanCheckpointPackets(
+	ctx QueryContext,
+	known map[uint64]*PacketInfo,
+	scannedHeight uint64,
+	queryRange func(QueryContext, uint64) (PacketInfoList, error),
+) (uint64, error) {
+	height := ctx.Height().GetRevisionHeight()
+	if height <= scannedHeight {
+		return scannedHeight, nil
+	}
+	if scannedHeight != 0 {
+		packets, err := queryRange(ctx, scannedHeight+1)
+		if err != nil {
+			return 0, err
+		}
+		for _, p := range packets {
+			known[p.Sequence] = p
+		}
+	}
+	return height, nil
+}
+
+// syncCheckpointPackets updates `known` to the pending packets with `seqs` and returns the oldest `limit` of them in ascending order of sequence.
+// Only the missing packets among the oldest `limit` sequences are queried, and the other missing ones are kept as nil
+// until a later relay reaches them. There is no limit if `limit` is zero.
+func syncCheckpointPackets(
+	ctx QueryContext,
+	known map[uint64]*PacketInfo,
+	seqs []uint64,
+	limit uint64,
+	queryOne func(QueryContext, uint64) (*PacketInfo, error),
+) (PacketInfoList, error) {
+	seqs = append([]uint64{}, seqs...)
+	sort.Slice(seqs, func(i, j int) bool {
+		return seqs[i] < seqs[j]
+	})
+	pending := make(map[uint64]struct{}, len(seqs))
+	for _, seq := range seqs {
+		pending[seq] = struct{}{}
+		if _, ok := known[seq]; !ok {
+			known[seq] = nil
+		}
+	}
+	// the packets that are no longer pending have been relayed
+	for seq := range known {
+		if _, ok := pending[seq]; !ok {
+			delete(known, seq)
+		}
+	}
+
+	if limit > 0 && uint64(len(seqs)) > limit {
+		seqs = seqs[:limit]
+	}
+	var packets PacketInfoList
+	for _, seq := range seqs {
+		p := known[seq]
+		if p == nil {
+			var err error
+			if p, err = queryOne(ctx, seq); err != nil {
+				return nil, err
+			}
+			known[seq] = p
+		}
+		// the timeout flag is evaluated at every relay
+		cp := *p
+		cp.TimedOut = false
+		packets = append(packets, &cp)
+	}
+	return packets, nil
+}
+
+// knownSequences returns the sequences of the packets in the checkpoint
+func knownSequences(known map[uint64]*PacketInfo) []uint64 {
+	seqs := make([]uint64, 0, len(known))
+	for seq := range known {
+		seqs = append(seqs, seq)
+	}
+	return seqs
+}
+
+// loadCheckpoint returns the checkpoint of the path end in the store, or an empty one that isn't persisted if `store` is nil
+func loadCheckpoint(store *CheckpointStore, pe *PathEnd) (*Checkpoint, error) {
+	if store == nil {
+		return newCheckpoint(), nil
+	}
+	return store.Load(pe)
+}
+
+// queryUnfinalizedRelayPackets returns the oldest `limit` unfinalized packets sent from `chain` and the number of all of them,
+// using the checkpoint if possible.
+// The sequences are queried before the packets so that only the packets to be returned are searched for.
+// Once the checkpoint is built, only the commitments of the known packets and the packets sent since the last relay are checked.
+func queryUnfinalizedRelayPackets(ctx QueryContext, store *CheckpointStore, chain, counterparty *ProvableChain, limit uint64) (PacketInfoList, int, error) {
+	q, ok := chain.Chain.(CheckpointQuerier)
+	if !ok {
+		packets, err := chain.QueryUnfinalizedRelayPackets(ctx, counterparty)
+		if err != nil {
+			return nil, 0, err
+		}
+		return limitPackets(chain, packets, limit), len(packets), nil
+	}
+	cp, err := loadCheckpoint(store, chain.Path())
+	if err != nil {
+		return nil, 0, err
+	}
+	prev := maps.Clone(cp.Packets)
+	height, err := scanCheckpointPackets(ctx, cp.Packets, cp.PacketsHeight, q.QuerySentPackets)
+	if err != nil {
+		return nil, 0, err
+	}
+	var seqs []uint64
+	if cp.PacketsHeight == 0 {
+		seqs, err = q.QueryPacketCommitmentSequences(ctx)
+	} else if seqs = knownSequences(cp.Packets); len(seqs) > 0 {
+		// UnreceivedAcks returns the sequences whose packet commitments exist on the chain
+		seqs, err = chain.QueryUnreceivedAcknowledgements(ctx, seqs)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if seqs, err = QueryUnreceivedOnCounterparty(ctx.Context(), counterparty, seqs, counterparty.QueryUnreceivedPackets); err != nil {
+		return nil, 0, err
+	}
+	packets, err := syncCheckpointPackets(ctx, cp.Packets, seqs, limit, q.QuerySentPacket)
+	if err != nil {
+		return nil, 0, err
+	}
+	logDeferredPackets(chain, len(packets), len(seqs))
+	if store == nil {
+		return packets, len(seqs), nil
+	}
+	if err := store.savePackets(chain.Path(), checkpointPackets, checkpointPacketsHeight, prev, cp.Packets, height); err != nil {
+		return nil, 0, err
+	}
+	return packets, len(seqs), nil
+}
+
+// queryUnfinalizedRelayAcknowledgements returns the oldest `limit` unfinalized packets received by `chain` with their acknowledgements
+// and the number of all of them, using the checkpoint if possible. Once the checkpoint is built, only the known acknowledgements and the ones written since the last relay are checked.
+func queryUnfinalizedRelayAcknowledgements(ctx QueryContext, store *CheckpointStore, chain, counterparty *ProvableChain, limit uint64) (PacketInfoList, int, error) {
+	q, ok := chain.Chain.(CheckpointQuerier)
+	if !ok {
+		packets, err := chain.QueryUnfinalizedRelayAcknowledgements(ctx, counterparty)
+		if err != nil {
+			return nil, 0, err
+		}
+		return limitPackets(chain, packets, limit), len(packets), nil
+	}
+	cp, err := loadCheckpoint(store, chain.Path())
+	if err != nil {
+		return nil, 0, err
+	}
+	prev := maps.Clone(cp.Acks)
+	height, err := scanCheckpointPackets(ctx, cp.Acks, cp.AcksHeight, q.QueryWrittenAcknowledgements)
+	if err != nil {
+		return nil, 0, err
+	}
+	var seqs []uint64
+	if cp.AcksHeight == 0 {
+		if seqs, err = q.QueryPacketAcknowledgementSequences(ctx); err != nil {
+			return nil, 0, err
+		}
+	} else {
+		// acknowledgements are never deleted, so the known ones are pending until they are received on the counterparty
+		seqs = knownSequences(cp.Acks)
+	}
+	if seqs, err = QueryUnreceivedOnCounterparty(ctx.Context(), counterparty, seqs, counterparty.QueryUnreceivedAcknowledgements); err != nil {
+		return nil, 0, err
+	}
+	packets, err := syncCheckpointPackets(ctx, cp.Acks, seqs, limit, q.QueryWrittenAcknowledgement)
+	if err != nil {
+		return nil, 0, err
+	}
+	logDeferredPackets(chain, len(packets), len(seqs))
+	if store == nil {
+		return packets, len(seqs), nil
+	}
+	if err := store.savePackets(chain.Path(), checkpointAcks, checkpointAcksHeight, prev, cp.Acks, height); err != nil {
+		return nil, 0, err
+	}
+	return packets, len(seqs), nil
+}
+
+// limitPackets returns the oldest `limit` packets
+func limitPackets(chain *ProvableChain, packets PacketInfoList, limit uint64) PacketInfoList {
+	ret := packets.Oldest(limit)
+	logDeferredPackets(chain, len(ret), len(packets))
+	return ret
+}
+
+func logDeferredPackets(chain *ProvableChain, count, total int) {
+	if count < total {
+		GetChainLogger(chain).Info("relaying the oldest packets, the rest are deferred", "count", count, "total", total)
+	}
+}
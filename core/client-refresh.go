@@ -0,0 +1,144 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ibcexported "github.com/cosmos/ibc-go/v7/modules/core/exported"
+)
+
+// TrustingPeriodProvider is an optional interface of Prover that provides the trusting period of
+// the light client of the chain on the counterparty chain
+type TrustingPeriodProvider interface {
+	// TrustingPeriod returns the trusting period of the light client
+	TrustingPeriod() time.Duration
+}
+
+// refreshClients updates the clients whose trusting periods are about to expire, or both clients if requested via the path control.
+// The ages of the clients are checked at every relay so that they are exported to the metrics and the readiness checks.
+func (srv *RelayService) refreshClients(ctx context.Context) error {
+	// the clients are updated regardless of the threshold if an operator requested it
+	force := srv.control.takeClientUpdate()
+	needsRefresh := func(chain, counterparty *ProvableChain) (bool, error) {
+		ok, err := srv.needsClientRefresh(ctx, chain, counterparty)
+		return force || ok, err
+	}
+
+	msgs := NewRelayMsgs()
+	// the client of src is on dst and vice versa
+	if ok, err := needsRefresh(srv.src, srv.dst); err != nil {
+		return err
+	} else if ok {
+		hs, err := setupHeadersForUpdate(ctx, srv.sh, srv.src, srv.dst)
+		if err != nil {
+			return err
+		}
+		if len(hs) > 0 {
+			addr, err := srv.dst.GetAddress()
+			if err != nil {
+				return err
+			}
+			msgs.Dst = srv.dst.Path().UpdateClients(hs, addr)
+		}
+	}
+	if ok, err := needsRefresh(srv.dst, srv.src); err != nil {
+		return err
+	} else if ok {
+		hs, err := setupHeadersForUpdate(ctx, srv.sh, srv.dst, srv.src)
+		if err != nil {
+			return err
+		}
+		if len(hs) > 0 {
+			addr, err := srv.src.GetAddress()
+			if err != nil {
+				return err
+			}
+			msgs.Src = srv.src.Path().UpdateClients(hs, addr)
+		}
+	}
+
+	if !msgs.Ready() {
+		return nil
+	}
+	if sendRelayMsgs(ctx, msgs, srv.src, srv.dst); !msgs.Success() {
+		return fmt.Errorf("failed to refresh the clients: %w", errors.Join(batchErrors(msgs.SrcResults, msgs.DstResults)...))
+	}
+	GetChainPairLogger(srv.src, srv.dst).Info("clients refreshed")
+	if len(msgs.Dst) > 0 {
+		srv.observeClientRefreshed(srv.src, srv.dst)
+	}
+	if len(msgs.Src) > 0 {
+		srv.observeClientRefreshed(srv.dst, srv.src)
+	}
+	return nil
+}
+
+// needsClientRefresh returns true if the threshold fraction of the trusting period has elapsed since
+// the latest consensus state of the client of `chain` on `counterparty`. The deadlines of the client are recorded
+// in the metrics and the path control, where the client should be refreshed by its expiry if the threshold isn't set.
+func (srv *RelayService) needsClientRefresh(ctx context.Context, chain, counterparty *ProvableChain) (bool, error) {
+	elapsed, period, ok, err := clientAge(srv.sh.GetQueryContext(ctx, counterparty.ChainID()), chain, counterparty)
+	if err != nil || !ok {
+		return false, err
+	}
+	refreshAfter := srv.observeClientAge(counterparty, elapsed, period)
+	if srv.clientRefreshThreshold <= 0 || elapsed < refreshAfter {
+		return false, nil
+	}
+	GetChainLogger(counterparty).Info("client is about to expire, refreshing it", "expires_in", period-elapsed)
+	return true, nil
+}
+
+// observeClientAge records the deadlines of the client on `counterparty` whose latest consensus state is `elapsed` old
+// and returns the age at which the client should be refreshed
+func (srv *RelayService) observeClientAge(counterparty *ProvableChain, elapsed, period time.Duration) time.Duration {
+	refreshAfter := period
+	if srv.clientRefreshThreshold > 0 {
+		refreshAfter = time.Duration(float64(period) * srv.clientRefreshThreshold)
+	}
+	now := time.Now()
+	metrics.observeClientExpiry(counterparty, period-elapsed)
+	srv.control.observeClientDeadline(counterparty, now.Add(refreshAfter-elapsed), now.Add(period-elapsed))
+	return refreshAfter
+}
+
+// observeClientRefreshed records the deadlines of the client of `chain` on `counterparty` that has just been updated.
+// The new consensus state is regarded as new as the current time, which its header is close to.
+func (srv *RelayService) observeClientRefreshed(chain, counterparty *ProvableChain) {
+	if tp, ok := chain.Prover.(TrustingPeriodProvider); ok && tp.TrustingPeriod() > 0 {
+		srv.observeClientAge(counterparty, 0, tp.TrustingPeriod())
+	}
+}
+
+// clientAge returns the time elapsed since the latest consensus state of the client of `chain` on `counterparty`
+// and the trusting period of the client. It returns false if the prover of `chain` doesn't provide the trusting period.
+func clientAge(queryCtx QueryContext, chain, counterparty *ProvableChain) (time.Duration, time.Duration, bool, error) {
+	tp, ok := chain.Prover.(TrustingPeriodProvider)
+	if !ok {
+		return 0, 0, false, nil
+	}
+	period := tp.TrustingPeriod()
+	if period <= 0 {
+		return 0, 0, false, nil
+	}
+
+	csRes, err := counterparty.QueryClientState(queryCtx)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to query the client state: %w", err)
+	}
+	var cs ibcexported.ClientState
+	if err := counterparty.Codec().UnpackAny(csRes.ClientState, &cs); err != nil {
+		return 0, 0, false, err
+	}
+	consRes, err := counterparty.QueryClientConsensusState(queryCtx, cs.GetLatestHeight())
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to query the consensus state: %w", err)
+	}
+	var cons ibcexported.ConsensusState
+	if err := counterparty.Codec().UnpackAny(consRes.ConsensusState, &cons); err != nil {
+		return 0, 0, false, err
+	}
+	return time.Since(time.Unix(0, int64(cons.GetTimestamp()))), period, true, nil
+}
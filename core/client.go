@@ -1,18 +1,18 @@
 package core
 
 import (
-	"log"
+	"context"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"golang.org/x/sync/errgroup"
 )
 
-func CreateClients(src, dst *ProvableChain) error {
+func CreateClients(ctx context.Context, src, dst *ProvableChain) error {
 	var (
 		clients = &RelayMsgs{Src: []sdk.Msg{}, Dst: []sdk.Msg{}}
 	)
 
-	srcH, dstH, err := getHeadersForCreateClient(src, dst)
+	srcH, dstH, err := getHeadersForCreateClient(ctx, src, dst)
 	if err != nil {
 		return err
 	}
@@ -45,24 +45,24 @@ func CreateClients(src, dst *ProvableChain) error {
 	// Send msgs to both chains
 	if clients.Ready() {
 		// TODO: Add retry here for out of gas or other errors
-		if clients.Send(src, dst); clients.Success() {
-			log.Printf("★ Clients created: [%s]client(%s) and [%s]client(%s)",
-				src.ChainID(), src.Path().ClientID, dst.ChainID(), dst.Path().ClientID)
+		// nothing is created in a dry run
+		if clients.Send(ctx, src, dst); clients.Success() && !src.IsDryRun() {
+			GetChainPairLogger(src, dst).Info("clients created")
 		}
 	}
 	return nil
 }
 
-func UpdateClients(src, dst *ProvableChain) error {
+func UpdateClients(ctx context.Context, src, dst *ProvableChain) error {
 	var (
 		clients = &RelayMsgs{Src: []sdk.Msg{}, Dst: []sdk.Msg{}}
 	)
 	// First, update the light clients to the latest header and return the header
-	sh, err := NewSyncHeaders(src, dst)
+	sh, err := NewSyncHeaders(ctx, src, dst)
 	if err != nil {
 		return err
 	}
-	srcUpdateHeaders, dstUpdateHeaders, err := sh.SetupBothHeadersForUpdate(src, dst)
+	srcUpdateHeaders, dstUpdateHeaders, err := sh.SetupBothHeadersForUpdate(ctx, src, dst)
 	if err != nil {
 		return err
 	}
@@ -74,23 +74,23 @@ func UpdateClients(src, dst *ProvableChain) error {
 	}
 	// Send msgs to both chains
 	if clients.Ready() {
-		if clients.Send(src, dst); clients.Success() {
-			log.Printf("★ Clients updated: [%s]client(%s) and [%s]client(%s)",
-				src.ChainID(), src.Path().ClientID, dst.ChainID(), dst.Path().ClientID)
+		// nothing is updated in a dry run
+		if clients.Send(ctx, src, dst); clients.Success() && !src.IsDryRun() {
+			GetChainPairLogger(src, dst).Info("clients updated")
 		}
 	}
 	return nil
 }
 
 // getHeadersForCreateClient calls UpdateLightWithHeader on the passed chains concurrently
-func getHeadersForCreateClient(src, dst LightClient) (srch, dsth Header, err error) {
+func getHeadersForCreateClient(ctx context.Context, src, dst LightClient) (srch, dsth Header, err error) {
 	var eg = new(errgroup.Group)
 	eg.Go(func() error {
-		srch, err = src.GetLatestFinalizedHeader()
+		srch, err = src.GetLatestFinalizedHeader(ctx)
 		return err
 	})
 	eg.Go(func() error {
-		dsth, err = dst.GetLatestFinalizedHeader()
+		dsth, err = dst.GetLatestFinalizedHeader(ctx)
 		return err
 	})
 	if err := eg.Wait(); err != nil {
@@ -13,6 +13,8 @@ import (
 type ChainProverConfig struct {
 	Chain  json.RawMessage `json:"chain" yaml:"chain"` // NOTE: it's any type as json format
 	Prover json.RawMessage `json:"prover" yaml:"prover"`
+	// RetryPolicy overrides the fields of the global retry policy for the chain
+	RetryPolicy *RetryPolicy `json:"retry-policy,omitempty" yaml:"retry-policy,omitempty"`
 
 	// cache
 	chain  ChainConfig  `json:"-" yaml:"-"`
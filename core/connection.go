@@ -1,30 +1,27 @@
 package core
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
-	retry "github.com/avast/retry-go"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
 	conntypes "github.com/cosmos/ibc-go/v7/modules/core/03-connection/types"
 	ibcexported "github.com/cosmos/ibc-go/v7/modules/core/exported"
 )
 
-var (
-	rtyAttNum = uint(5)
-	rtyAtt    = retry.Attempts(rtyAttNum)
-	rtyDel    = retry.Delay(time.Millisecond * 400)
-	rtyErr    = retry.LastErrorOnly(true)
-)
-
-func CreateConnection(src, dst *ProvableChain, to time.Duration) error {
+func CreateConnection(ctx context.Context, src, dst *ProvableChain, to time.Duration) error {
 	ticker := time.NewTicker(to)
+	defer ticker.Stop()
 
-	failed := 0
+	var failed uint
 	for ; true; <-ticker.C {
-		connSteps, err := createConnectionStep(src, dst)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		connSteps, err := createConnectionStep(ctx, src, dst)
 		if err != nil {
 			return err
 		}
@@ -33,30 +30,34 @@ func CreateConnection(src, dst *ProvableChain, to time.Duration) error {
 			break
 		}
 
-		connSteps.Send(src, dst)
+		connSteps.Send(ctx, src, dst)
+		// the next steps can't be built in a dry run because they depend on the result of this one
+		if src.IsDryRun() {
+			return nil
+		}
 
 		switch {
 		// In the case of success and this being the last transaction
 		// debug logging, log created connection and break
 		case connSteps.Success() && connSteps.Last:
-			log.Printf("★ Connection created: [%s]client{%s}conn{%s} -> [%s]client{%s}conn{%s}",
-				src.ChainID(), src.Path().ClientID, src.Path().ConnectionID,
-				dst.ChainID(), dst.Path().ClientID, dst.Path().ConnectionID)
+			GetChainPairLogger(src, dst).Info("connection created")
 			return nil
 		// In the case of success, reset the failures counter
 		case connSteps.Success():
 			failed = 0
 			continue
-		// In the case of failure, increment the failures counter and exit if this is the 3rd failure
+		// In the case of failure, increment the failures counter and exit if the attempts of the retry policy are exhausted
 		case !connSteps.Success():
 			failed++
-			log.Println("retrying transaction...")
-			time.Sleep(5 * time.Second)
-			if failed > 2 {
+			if failed >= src.RetryPolicy().GetAttempts() {
 				return fmt.Errorf("! Connection failed: [%s]client{%s}conn{%s} -> [%s]client{%s}conn{%s}",
 					src.ChainID(), src.Path().ClientID, src.Path().ConnectionID,
 					dst.ChainID(), dst.Path().ClientID, dst.Path().ConnectionID)
 			}
+			GetChainPairLogger(src, dst).Info("retrying connection handshake", "failures", failed)
+			if err := sleepContext(ctx, src.RetryPolicy().Backoff(failed)); err != nil {
+				return err
+			}
 		}
 
 	}
@@ -64,13 +65,13 @@ func CreateConnection(src, dst *ProvableChain, to time.Duration) error {
 	return nil
 }
 
-func createConnectionStep(src, dst *ProvableChain) (*RelayMsgs, error) {
+func createConnectionStep(ctx context.Context, src, dst *ProvableChain) (*RelayMsgs, error) {
 	out := NewRelayMsgs()
 	if err := validatePaths(src, dst); err != nil {
 		return nil, err
 	}
 	// First, update the light clients to the latest header and return the header
-	sh, err := NewSyncHeaders(src, dst)
+	sh, err := NewSyncHeaders(ctx, src, dst)
 	if err != nil {
 		return nil, err
 	}
@@ -82,27 +83,27 @@ func createConnectionStep(src, dst *ProvableChain) (*RelayMsgs, error) {
 		srcCons, dstCons                   *clienttypes.QueryConsensusStateResponse
 		srcConsH, dstConsH                 ibcexported.Height
 	)
-	err = retry.Do(func() error {
-		srcUpdateHeaders, dstUpdateHeaders, err = sh.SetupBothHeadersForUpdate(src, dst)
+	err = src.RetryPolicy().Do(ctx, func() error {
+		srcUpdateHeaders, dstUpdateHeaders, err = sh.SetupBothHeadersForUpdate(ctx, src, dst)
 		return err
-	}, rtyAtt, rtyDel, rtyErr, retry.OnRetry(func(n uint, err error) {
+	}, func(n uint, err error) {
 		// logRetryUpdateHeaders(src, dst, n, err)
-		if err := sh.Updates(src, dst); err != nil {
+		if err := sh.Updates(ctx, src, dst); err != nil {
 			panic(err)
 		}
-	}))
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	srcConn, dstConn, err := QueryConnectionPair(sh.GetQueryContext(src.ChainID()), sh.GetQueryContext(dst.ChainID()), src, dst)
+	srcConn, dstConn, err := QueryConnectionPair(sh.GetQueryContext(ctx, src.ChainID()), sh.GetQueryContext(ctx, dst.ChainID()), src, dst)
 	if err != nil {
 		return nil, err
 	}
 
 	if !(srcConn.Connection.State == conntypes.UNINITIALIZED && dstConn.Connection.State == conntypes.UNINITIALIZED) {
 		// Query client state from each chain's client
-		srcCsRes, dstCsRes, err = QueryClientStatePair(sh.GetQueryContext(src.ChainID()), sh.GetQueryContext(dst.ChainID()), src, dst)
+		srcCsRes, dstCsRes, err = QueryClientStatePair(sh.GetQueryContext(ctx, src.ChainID()), sh.GetQueryContext(ctx, dst.ChainID()), src, dst)
 		if err != nil && (srcCsRes == nil || dstCsRes == nil) {
 			return nil, err
 		}
@@ -116,7 +117,7 @@ func createConnectionStep(src, dst *ProvableChain) (*RelayMsgs, error) {
 		// Store the heights
 		srcConsH, dstConsH = srcCS.GetLatestHeight(), dstCS.GetLatestHeight()
 		srcCons, dstCons, err = QueryClientConsensusStatePair(
-			sh.GetQueryContext(src.ChainID()), sh.GetQueryContext(dst.ChainID()),
+			sh.GetQueryContext(ctx, src.ChainID()), sh.GetQueryContext(ctx, dst.ChainID()),
 			src, dst, srcConsH, dstConsH)
 		if err != nil {
 			return nil, err
@@ -131,7 +132,7 @@ func createConnectionStep(src, dst *ProvableChain) (*RelayMsgs, error) {
 		if len(dstUpdateHeaders) > 0 {
 			out.Src = append(out.Src, src.Path().UpdateClients(dstUpdateHeaders, addr)...)
 		}
-		out.Src = append(out.Src, src.Path().ConnInit(dst.Path(), addr))
+		out.Src = append(out.Src, src.Path().ConnInit(dst.Path(), GetCommitmentPrefix(dst), addr))
 		// Handshake has started on dst (1 stepdone), relay `connOpenTry` and `updateClient` on src
 	case srcConn.Connection.State == conntypes.UNINITIALIZED && dstConn.Connection.State == conntypes.INIT:
 		logConnectionStates(src, dst, srcConn, dstConn)
@@ -139,7 +140,7 @@ func createConnectionStep(src, dst *ProvableChain) (*RelayMsgs, error) {
 		if len(dstUpdateHeaders) > 0 {
 			out.Src = append(out.Src, src.Path().UpdateClients(dstUpdateHeaders, addr)...)
 		}
-		out.Src = append(out.Src, src.Path().ConnTry(dst.Path(), dstCsRes, dstConn, dstCons, addr))
+		out.Src = append(out.Src, src.Path().ConnTry(dst.Path(), GetCommitmentPrefix(dst), dstCsRes, dstConn, dstCons, addr))
 	// Handshake has started on src (1 step done), relay `connOpenTry` and `updateClient` on dst
 	case srcConn.Connection.State == conntypes.INIT && dstConn.Connection.State == conntypes.UNINITIALIZED:
 		logConnectionStates(dst, src, dstConn, srcConn)
@@ -147,7 +148,7 @@ func createConnectionStep(src, dst *ProvableChain) (*RelayMsgs, error) {
 		if len(srcUpdateHeaders) > 0 {
 			out.Dst = append(out.Dst, dst.Path().UpdateClients(srcUpdateHeaders, addr)...)
 		}
-		out.Dst = append(out.Dst, dst.Path().ConnTry(src.Path(), srcCsRes, srcConn, srcCons, addr))
+		out.Dst = append(out.Dst, dst.Path().ConnTry(src.Path(), GetCommitmentPrefix(src), srcCsRes, srcConn, srcCons, addr))
 
 	// Handshake has started on src end (2 steps done), relay `connOpenAck` and `updateClient` to dst end
 	case srcConn.Connection.State == conntypes.TRYOPEN && dstConn.Connection.State == conntypes.INIT:
@@ -206,15 +207,9 @@ func validatePaths(src, dst Chain) error {
 }
 
 func logConnectionStates(src, dst Chain, srcConn, dstConn *conntypes.QueryConnectionResponse) {
-	log.Printf("- [%s]@{%d}conn(%s)-{%s} : [%s]@{%d}conn(%s)-{%s}",
-		src.ChainID(),
-		mustGetHeight(srcConn.ProofHeight),
-		src.Path().ConnectionID,
-		srcConn.Connection.State,
-		dst.ChainID(),
-		mustGetHeight(dstConn.ProofHeight),
-		dst.Path().ConnectionID,
-		dstConn.Connection.State,
+	GetChainPairLogger(src, dst).Info("connection states",
+		slog.Group("src", "height", mustGetHeight(srcConn.ProofHeight), "state", srcConn.Connection.State.String()),
+		slog.Group("dst", "height", mustGetHeight(dstConn.ProofHeight), "state", dstConn.Connection.State.String()),
 	)
 }
 
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MsgSimulator is an optional interface of Chain that estimates the cost of a transaction without broadcasting it
+type MsgSimulator interface {
+	// SimulateMsgs simulates a transaction of the msgs signed and priced in the same way as Send
+	SimulateMsgs(ctx context.Context, msgs []sdk.Msg) (*SimulationResult, error)
+}
+
+// SimulationResult is the estimated cost of a transaction
+type SimulationResult struct {
+	GasUsed uint64    `json:"gas_used"`
+	Gas     uint64    `json:"gas"` // gas limit that would be set to the transaction
+	Fee     sdk.Coins `json:"fee"`
+}
+
+// DryRun records the transactions that the chains would submit instead of broadcasting them
+type DryRun struct {
+	mtx sync.Mutex
+	txs []*DryRunTx
+}
+
+// DryRunTx is a transaction that a chain would submit in a dry run
+type DryRunTx struct {
+	ChainID    string            `json:"chain_id"`
+	Msgs       []json.RawMessage `json:"msgs"`
+	Simulation *SimulationResult `json:"simulation,omitempty"`
+	Error      string            `json:"error,omitempty"` // the reason why the transaction couldn't be simulated
+}
+
+// NewDryRun returns a new dry run
+func NewDryRun() *DryRun {
+	return &DryRun{}
+}
+
+// Txs returns the transactions recorded so far in the order of submission.
+// The transactions to the same chain keep their order, but the ones to different chains may interleave.
+func (d *DryRun) Txs() []*DryRunTx {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return append([]*DryRunTx{}, d.txs...)
+}
+
+// record simulates the msgs on the chain and records them as a transaction.
+// A failed simulation is recorded in the transaction instead of being returned so that the following batches are recorded too.
+func (d *DryRun) record(ctx context.Context, chain Chain, msgs []sdk.Msg) error {
+	tx := &DryRunTx{ChainID: chain.ChainID()}
+	for _, msg := range msgs {
+		bz, err := chain.Codec().MarshalInterfaceJSON(msg)
+		if err != nil {
+			return err
+		}
+		tx.Msgs = append(tx.Msgs, bz)
+	}
+	if sim, ok := chain.(MsgSimulator); !ok {
+		tx.Error = "the chain doesn't support simulations"
+	} else if res, err := sim.SimulateMsgs(ctx, msgs); err != nil {
+		tx.Error = err.Error()
+	} else {
+		tx.Simulation = res
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.txs = append(d.txs, tx)
+	return nil
+}
+
+// SetDryRun makes the chain record the transactions to `d` instead of broadcasting them
+func (pc *ProvableChain) SetDryRun(d *DryRun) {
+	pc.dryRun = d
+}
+
+// IsDryRun returns true if the chain is in a dry run
+func (pc *ProvableChain) IsDryRun() bool {
+	return pc.dryRun != nil
+}
+
+// SendMsgs sends msgs to the chain, or records them if the chain is in a dry run
+func (pc *ProvableChain) SendMsgs(ctx context.Context, msgs []sdk.Msg) ([]byte, error) {
+	if pc.dryRun == nil {
+		return pc.Chain.SendMsgs(ctx, msgs)
+	}
+	return nil, pc.dryRun.record(ctx, pc.Chain, msgs)
+}
+
+// Send sends msgs to the chain, or records them if the chain is in a dry run, in which case the result is nil
+func (pc *ProvableChain) Send(ctx context.Context, msgs []sdk.Msg) (*TxResult, error) {
+	if pc.dryRun == nil {
+		return pc.Chain.Send(ctx, msgs)
+	}
+	return nil, pc.dryRun.record(ctx, pc.Chain, msgs)
+}
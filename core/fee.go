@@ -0,0 +1,137 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	feetypes "github.com/cosmos/ibc-go/v7/modules/apps/29-fee/types"
+	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
+)
+
+// ICS29Querier is an optional interface of Chain to the state of ICS-29 fee middleware
+type ICS29Querier interface {
+	// QueryIncentivizedPacket returns the fees escrowed for the packet.
+	// It returns a nil response if no fees are escrowed.
+	QueryIncentivizedPacket(ctx QueryContext, packetID chantypes.PacketId) (*feetypes.QueryIncentivizedPacketResponse, error)
+}
+
+// FeeWrappedVersion returns the channel version that negotiates the fee middleware on top of the given application version
+func FeeWrappedVersion(appVersion string) string {
+	return string(feetypes.ModuleCdc.MustMarshalJSON(&feetypes.Metadata{
+		FeeVersion: feetypes.Version,
+		AppVersion: appVersion,
+	}))
+}
+
+// parseFeeVersion returns the fee metadata if the version is fee-wrapped
+func parseFeeVersion(version string) (*feetypes.Metadata, bool, error) {
+	if !strings.HasPrefix(strings.TrimSpace(version), "{") {
+		return nil, false, nil
+	}
+	var metadata feetypes.Metadata
+	if err := feetypes.ModuleCdc.UnmarshalJSON([]byte(version), &metadata); err != nil {
+		return nil, false, fmt.Errorf("failed to parse the fee-wrapped version '%s': %w", version, err)
+	}
+	return &metadata, true, nil
+}
+
+// IsFeeEnabled returns true if the channel version of the path end negotiates the fee middleware
+func (pe *PathEnd) IsFeeEnabled() bool {
+	_, ok, err := parseFeeVersion(pe.Version)
+	return err == nil && ok
+}
+
+// RegisterPayee creates an sdk.Msg to register the payee of the relayer for the channel on src
+func (pe *PathEnd) RegisterPayee(payee string, signer sdk.AccAddress) sdk.Msg {
+	return feetypes.NewMsgRegisterPayee(pe.PortID, pe.ChannelID, signer.String(), payee)
+}
+
+// RegisterCounterpartyPayee creates an sdk.Msg to register the counterparty payee of the relayer for the channel on src
+func (pe *PathEnd) RegisterCounterpartyPayee(counterpartyPayee string, signer sdk.AccAddress) sdk.Msg {
+	return feetypes.NewMsgRegisterCounterpartyPayee(pe.PortID, pe.ChannelID, signer.String(), counterpartyPayee)
+}
+
+// RegisterPayee registers the payee of the relayer for the channel on src
+func RegisterPayee(ctx context.Context, src, dst *ProvableChain, payee string) error {
+	signer, err := src.GetAddress()
+	if err != nil {
+		return err
+	}
+	return sendFeeMsg(ctx, src, dst, src.Path().RegisterPayee(payee, signer), "payee")
+}
+
+// RegisterCounterpartyPayee registers the counterparty payee of the relayer for the channel on src
+func RegisterCounterpartyPayee(ctx context.Context, src, dst *ProvableChain, counterpartyPayee string) error {
+	signer, err := src.GetAddress()
+	if err != nil {
+		return err
+	}
+	return sendFeeMsg(ctx, src, dst, src.Path().RegisterCounterpartyPayee(counterpartyPayee, signer), "counterparty payee")
+}
+
+func sendFeeMsg(ctx context.Context, src, dst *ProvableChain, msg sdk.Msg, name string) error {
+	msgs := NewRelayMsgs()
+	msgs.Src = []sdk.Msg{msg}
+	if msgs.Send(ctx, src, dst); !msgs.Success() {
+		return fmt.Errorf("failed to register the %s on chain %s", name, src.ChainID())
+	}
+	GetChainLogger(src).Info("registered the " + name)
+	return nil
+}
+
+// packetFeeKind represents which fee in a PacketFee incentivizes a relay
+type packetFeeKind int
+
+const (
+	recvFee packetFeeKind = iota
+	ackFee
+	timeoutFee
+)
+
+// filterByIncentive returns the packets whose total fee of the given kind escrowed on `origin` is above `threshold` in any denom.
+// `origin` must be the chain that sent the packets.
+func filterByIncentive(ctx QueryContext, origin *ProvableChain, packets PacketInfoList, kind packetFeeKind, threshold sdk.Coins) (PacketInfoList, error) {
+	if threshold.Empty() || len(packets) == 0 {
+		return packets, nil
+	}
+	querier, ok := origin.Chain.(ICS29Querier)
+	if !ok {
+		return nil, fmt.Errorf("chain %s does not support ICS-29 queries", origin.ChainID())
+	}
+
+	var ret PacketInfoList
+	for _, p := range packets {
+		packetID := chantypes.NewPacketID(p.SourcePort, p.SourceChannel, p.Sequence)
+		res, err := querier.QueryIncentivizedPacket(ctx, packetID)
+		if err != nil {
+			return nil, err
+		}
+		// a timed-out packet is incentivized by the timeout fee instead of the recv fee
+		k := kind
+		if k == recvFee && p.TimedOut {
+			k = timeoutFee
+		}
+		var total sdk.Coins
+		if res != nil {
+			for _, pf := range res.IncentivizedPacket.PacketFees {
+				switch k {
+				case recvFee:
+					total = total.Add(pf.Fee.RecvFee...)
+				case ackFee:
+					total = total.Add(pf.Fee.AckFee...)
+				case timeoutFee:
+					total = total.Add(pf.Fee.TimeoutFee...)
+				}
+			}
+		}
+		if total.IsAnyGTE(threshold) {
+			ret = append(ret, p)
+		}
+	}
+	if num := len(packets) - len(ret); num > 0 {
+		GetChainLogger(origin).Info("skipped the packets not incentivized above the threshold", "count", num, "threshold", threshold.String())
+	}
+	return ret, nil
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+)
+
+// PacketFilter defines which packets are relayed on a path.
+// A packet is relayed if it matches any of the allow rules (or no allow rule is given) and matches none of the deny rules.
+type PacketFilter struct {
+	Allow []PacketFilterRule `yaml:"allow,omitempty" json:"allow,omitempty"`
+	Deny  []PacketFilterRule `yaml:"deny,omitempty" json:"deny,omitempty"`
+
+	// allow and deny are the compiled rules, set by Compile
+	allow, deny []*packetMatcher
+	compiled    bool
+}
+
+// PacketFilterRule matches a packet that satisfies all the conditions set in the rule.
+// The port and channel conditions are compared with the source/destination of the packet, not of the path.
+// The sender, receiver, denom, min-amount and memo conditions match only ICS-20 packets.
+type PacketFilterRule struct {
+	SrcPort     string `yaml:"src-port,omitempty" json:"src-port,omitempty"`
+	SrcChannel  string `yaml:"src-channel,omitempty" json:"src-channel,omitempty"`
+	DstPort     string `yaml:"dst-port,omitempty" json:"dst-port,omitempty"`
+	DstChannel  string `yaml:"dst-channel,omitempty" json:"dst-channel,omitempty"`
+	Sender      string `yaml:"sender,omitempty" json:"sender,omitempty"`
+	Receiver    string `yaml:"receiver,omitempty" json:"receiver,omitempty"`
+	Denom       string `yaml:"denom,omitempty" json:"denom,omitempty"`
+	MinAmount   string `yaml:"min-amount,omitempty" json:"min-amount,omitempty"`
+	Memo        string `yaml:"memo,omitempty" json:"memo,omitempty"` // regular expression
+	MinSequence uint64 `yaml:"min-sequence,omitempty" json:"min-sequence,omitempty"`
+	MaxSequence uint64 `yaml:"max-sequence,omitempty" json:"max-sequence,omitempty"`
+}
+
+// packetMatcher is a PacketFilterRule whose conditions are parsed
+type packetMatcher struct {
+	rule      PacketFilterRule
+	memo      *regexp.Regexp
+	minAmount *sdk.Int
+}
+
+// UnmarshalJSON implements json.Unmarshaler so that the rules are compiled when the config is loaded
+// and an invalid rule fails there instead of when packets are relayed
+func (f *PacketFilter) UnmarshalJSON(bz []byte) error {
+	type rules PacketFilter
+	var v rules
+	if err := json.Unmarshal(bz, &v); err != nil {
+		return err
+	}
+	*f = PacketFilter{Allow: v.Allow, Deny: v.Deny}
+	return f.Compile()
+}
+
+// Validate checks that the rules of the filter are valid
+func (f *PacketFilter) Validate() error {
+	_, _, err := f.compile()
+	return err
+}
+
+// Compile validates the rules and caches the compiled rules used by Apply.
+// It must be called again after the rules are modified.
+func (f *PacketFilter) Compile() error {
+	allow, deny, err := f.compile()
+	if err != nil {
+		return err
+	}
+	f.allow, f.deny, f.compiled = allow, deny, true
+	return nil
+}
+
+// Apply returns the packets that pass the filter.
+// The rules are compiled on each call if the filter has not been compiled by Compile.
+func (f *PacketFilter) Apply(packets PacketInfoList) (PacketInfoList, error) {
+	if f == nil || (len(f.Allow) == 0 && len(f.Deny) == 0) {
+		return packets, nil
+	}
+	allow, deny := f.allow, f.deny
+	if !f.compiled {
+		var err error
+		if allow, deny, err = f.compile(); err != nil {
+			return nil, err
+		}
+	}
+	var ret PacketInfoList
+	for _, p := range packets {
+		if len(allow) > 0 && !matchAny(allow, p) {
+			continue
+		}
+		if matchAny(deny, p) {
+			continue
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+func (f *PacketFilter) compile() (allow, deny []*packetMatcher, err error) {
+	for i, r := range f.Allow {
+		m, err := r.compile()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid allow rule #%d: %w", i, err)
+		}
+		allow = append(allow, m)
+	}
+	for i, r := range f.Deny {
+		m, err := r.compile()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid deny rule #%d: %w", i, err)
+		}
+		deny = append(deny, m)
+	}
+	return allow, deny, nil
+}
+
+func (r PacketFilterRule) compile() (*packetMatcher, error) {
+	m := &packetMatcher{rule: r}
+	if r.Memo != "" {
+		re, err := regexp.Compile(r.Memo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memo pattern '%s': %w", r.Memo, err)
+		}
+		m.memo = re
+	}
+	if r.MinAmount != "" {
+		amount, ok := sdk.NewIntFromString(r.MinAmount)
+		if !ok {
+			return nil, fmt.Errorf("invalid min-amount '%s'", r.MinAmount)
+		}
+		m.minAmount = &amount
+	}
+	if r.MaxSequence != 0 && r.MinSequence > r.MaxSequence {
+		return nil, fmt.Errorf("min-sequence (%d) is greater than max-sequence (%d)", r.MinSequence, r.MaxSequence)
+	}
+	return m, nil
+}
+
+func (m *packetMatcher) requiresTransferData() bool {
+	r := m.rule
+	return r.Sender != "" || r.Receiver != "" || r.Denom != "" || m.minAmount != nil || m.memo != nil
+}
+
+func (m *packetMatcher) match(p *PacketInfo) bool {
+	r := m.rule
+	if (r.SrcPort != "" && r.SrcPort != p.SourcePort) ||
+		(r.SrcChannel != "" && r.SrcChannel != p.SourceChannel) ||
+		(r.DstPort != "" && r.DstPort != p.DestinationPort) ||
+		(r.DstChannel != "" && r.DstChannel != p.DestinationChannel) ||
+		(r.MinSequence != 0 && p.Sequence < r.MinSequence) ||
+		(r.MaxSequence != 0 && p.Sequence > r.MaxSequence) {
+		return false
+	}
+	if !m.requiresTransferData() {
+		return true
+	}
+
+	var data transfertypes.FungibleTokenPacketData
+	if err := transfertypes.ModuleCdc.UnmarshalJSON(p.GetData(), &data); err != nil {
+		return false
+	}
+	if (r.Sender != "" && r.Sender != data.Sender) ||
+		(r.Receiver != "" && r.Receiver != data.Receiver) ||
+		(r.Denom != "" && r.Denom != data.Denom) ||
+		(m.memo != nil && !m.memo.MatchString(data.Memo)) {
+		return false
+	}
+	if m.minAmount != nil {
+		amount, ok := sdk.NewIntFromString(data.Amount)
+		if !ok || amount.LT(*m.minAmount) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(ms []*packetMatcher, p *PacketInfo) bool {
+	for _, m := range ms {
+		if m.match(p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	transfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
+)
+
+func transferPacket(seq uint64, srcChannel string, data transfertypes.FungibleTokenPacketData) *PacketInfo {
+	return &PacketInfo{
+		Packet: chantypes.Packet{
+			Sequence:           seq,
+			SourcePort:         "transfer",
+			SourceChannel:      srcChannel,
+			DestinationPort:    "transfer",
+			DestinationChannel: "channel-9",
+			Data:               data.GetBytes(),
+		},
+	}
+}
+
+func TestPacketFilterApply(t *testing.T) {
+	packets := PacketInfoList{
+		transferPacket(1, "channel-0", transfertypes.NewFungibleTokenPacketData("uatom", "100", "alice", "bob", "")),
+		transferPacket(2, "channel-0", transfertypes.NewFungibleTokenPacketData("uatom", "5", "alice", "bob", "")),
+		transferPacket(3, "channel-1", transfertypes.NewFungibleTokenPacketData("uosmo", "1000", "carol", "bob", "swap:1")),
+		{Packet: chantypes.Packet{Sequence: 4, SourcePort: "icahost", SourceChannel: "channel-0", Data: []byte("not ics20")}},
+	}
+
+	cases := []struct {
+		name   string
+		filter *PacketFilter
+		want   []uint64
+	}{
+		{"nil filter", nil, []uint64{1, 2, 3, 4}},
+		{"empty filter", &PacketFilter{}, []uint64{1, 2, 3, 4}},
+		{"allow channel", &PacketFilter{Allow: []PacketFilterRule{{SrcChannel: "channel-0"}}}, []uint64{1, 2, 4}},
+		{"deny channel", &PacketFilter{Deny: []PacketFilterRule{{SrcChannel: "channel-0"}}}, []uint64{3}},
+		{"allow denom skips non-ics20", &PacketFilter{Allow: []PacketFilterRule{{Denom: "uatom"}}}, []uint64{1, 2}},
+		{"min amount", &PacketFilter{Allow: []PacketFilterRule{{MinAmount: "100"}}}, []uint64{1, 3}},
+		{"memo pattern", &PacketFilter{Allow: []PacketFilterRule{{Memo: "^swap:"}}}, []uint64{3}},
+		{"sequence bounds", &PacketFilter{Allow: []PacketFilterRule{{MinSequence: 2, MaxSequence: 3}}}, []uint64{2, 3}},
+		{"any allow rule", &PacketFilter{Allow: []PacketFilterRule{{Sender: "carol"}, {SrcPort: "icahost"}}}, []uint64{3, 4}},
+		{"all conditions of a rule", &PacketFilter{Allow: []PacketFilterRule{{Sender: "alice", MinAmount: "10"}}}, []uint64{1}},
+		{"deny wins over allow", &PacketFilter{
+			Allow: []PacketFilterRule{{SrcChannel: "channel-0"}},
+			Deny:  []PacketFilterRule{{Receiver: "bob", MinAmount: "50"}},
+		}, []uint64{2, 4}},
+	}
+	for _, c := range cases {
+		for _, compiled := range []bool{false, true} {
+			if compiled && c.filter != nil {
+				if err := c.filter.Compile(); err != nil {
+					t.Fatalf("%s: failed to compile: %v", c.name, err)
+				}
+			}
+			got, err := c.filter.Apply(packets)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", c.name, err)
+			}
+			if seqs := got.ExtractSequenceList(); !reflect.DeepEqual(seqs, c.want) {
+				t.Errorf("%s (compiled=%v): got %v, want %v", c.name, compiled, seqs, c.want)
+			}
+		}
+	}
+}
+
+func TestPacketFilterValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		rule    PacketFilterRule
+		wantErr bool
+	}{
+		{"valid", PacketFilterRule{Memo: "^swap:", MinAmount: "1", MinSequence: 1, MaxSequence: 1}, false},
+		{"invalid memo", PacketFilterRule{Memo: "("}, true},
+		{"invalid min amount", PacketFilterRule{MinAmount: "1.5"}, true},
+		{"min sequence above max", PacketFilterRule{MinSequence: 3, MaxSequence: 2}, true},
+	}
+	for _, c := range cases {
+		for _, f := range []*PacketFilter{{Allow: []PacketFilterRule{c.rule}}, {Deny: []PacketFilterRule{c.rule}}} {
+			if err := f.Validate(); (err != nil) != c.wantErr {
+				t.Errorf("%s: Validate returned %v, want error: %v", c.name, err, c.wantErr)
+			}
+			if _, err := f.Apply(nil); (err != nil) != c.wantErr {
+				t.Errorf("%s: Apply returned %v, want error: %v", c.name, err, c.wantErr)
+			}
+		}
+	}
+}
+
+func TestPacketFilterUnmarshalJSON(t *testing.T) {
+	var p Path
+	if err := json.Unmarshal([]byte(`{"filter":{"allow":[{"memo":"^swap:"}]}}`), &p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !p.Filter.compiled || len(p.Filter.allow) != 1 || p.Filter.allow[0].memo == nil {
+		t.Errorf("filter is not compiled on load: %+v", p.Filter)
+	}
+
+	if err := json.Unmarshal([]byte(`{"filter":{"deny":[{"memo":"("}]}}`), &p); err == nil {
+		t.Error("invalid memo pattern is accepted on load")
+	}
+}
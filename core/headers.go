@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	retry "github.com/avast/retry-go"
 	"github.com/cosmos/ibc-go/v7/modules/core/exported"
 )
 
@@ -16,19 +17,20 @@ type Header interface {
 // It also provides the helper functions to update the clients on the chains
 type SyncHeaders interface {
 	// Updates updates the headers on both chains
-	Updates(src, dst ChainInfoLightClient) error
+	Updates(ctx context.Context, src, dst ChainInfoLightClient) error
 
 	// GetLatestFinalizedHeader returns the latest finalized header of the chain
 	GetLatestFinalizedHeader(chainID string) Header
 
-	// GetQueryContext builds a query context based on the latest finalized header
-	GetQueryContext(chainID string) QueryContext
+	// GetQueryContext builds a query context based on the latest finalized header.
+	// The spans of the queries with the context are children of the span in `ctx`.
+	GetQueryContext(ctx context.Context, chainID string) QueryContext
 
 	// SetupHeadersForUpdate returns `src` chain's headers needed to update the client on `dst` chain
-	SetupHeadersForUpdate(src, dst ChainICS02QuerierLightClient) ([]Header, error)
+	SetupHeadersForUpdate(ctx context.Context, src, dst ChainICS02QuerierLightClient) ([]Header, error)
 
 	// SetupBothHeadersForUpdate returns both `src` and `dst` chain's headers needed to update the clients on each chain
-	SetupBothHeadersForUpdate(src, dst ChainICS02QuerierLightClient) (srcHeaders []Header, dstHeaders []Header, err error)
+	SetupBothHeadersForUpdate(ctx context.Context, src, dst ChainICS02QuerierLightClient) (srcHeaders []Header, dstHeaders []Header, err error)
 }
 
 // ChainInfoLightClient = ChainInfo + LightClient
@@ -51,30 +53,30 @@ var _ SyncHeaders = (*syncHeaders)(nil)
 
 // NewSyncHeaders returns a new instance of SyncHeaders that can be easily
 // kept "reasonably up to date"
-func NewSyncHeaders(src, dst ChainInfoLightClient) (SyncHeaders, error) {
+func NewSyncHeaders(ctx context.Context, src, dst ChainInfoLightClient) (SyncHeaders, error) {
 	if err := ensureDifferentChains(src, dst); err != nil {
 		return nil, err
 	}
 	sh := &syncHeaders{
 		latestFinalizedHeaders: map[string]Header{src.ChainID(): nil, dst.ChainID(): nil},
 	}
-	if err := sh.Updates(src, dst); err != nil {
+	if err := sh.Updates(ctx, src, dst); err != nil {
 		return nil, err
 	}
 	return sh, nil
 }
 
 // Updates updates the headers on both chains
-func (sh *syncHeaders) Updates(src, dst ChainInfoLightClient) error {
+func (sh *syncHeaders) Updates(ctx context.Context, src, dst ChainInfoLightClient) error {
 	if err := ensureDifferentChains(src, dst); err != nil {
 		return err
 	}
 
-	srcHeader, err := src.GetLatestFinalizedHeader()
+	srcHeader, err := src.GetLatestFinalizedHeader(ctx)
 	if err != nil {
 		return err
 	}
-	dstHeader, err := dst.GetLatestFinalizedHeader()
+	dstHeader, err := dst.GetLatestFinalizedHeader(ctx)
 	if err != nil {
 		return err
 	}
@@ -90,25 +92,25 @@ func (sh syncHeaders) GetLatestFinalizedHeader(chainID string) Header {
 }
 
 // GetQueryContext builds a query context based on the latest finalized header
-func (sh syncHeaders) GetQueryContext(chainID string) QueryContext {
-	return NewQueryContext(context.TODO(), sh.GetLatestFinalizedHeader(chainID).GetHeight())
+func (sh syncHeaders) GetQueryContext(ctx context.Context, chainID string) QueryContext {
+	return NewQueryContext(ctx, sh.GetLatestFinalizedHeader(chainID).GetHeight())
 }
 
 // SetupHeadersForUpdate returns `src` chain's headers to update the client on `dst` chain
-func (sh syncHeaders) SetupHeadersForUpdate(src, dst ChainICS02QuerierLightClient) ([]Header, error) {
+func (sh syncHeaders) SetupHeadersForUpdate(ctx context.Context, src, dst ChainICS02QuerierLightClient) ([]Header, error) {
 	if err := ensureDifferentChains(src, dst); err != nil {
 		return nil, err
 	}
-	return src.SetupHeadersForUpdate(dst, sh.GetLatestFinalizedHeader(src.ChainID()))
+	return src.SetupHeadersForUpdate(ctx, dst, sh.GetLatestFinalizedHeader(src.ChainID()))
 }
 
 // SetupBothHeadersForUpdate returns both `src` and `dst` chain's headers to update the clients on each chain
-func (sh syncHeaders) SetupBothHeadersForUpdate(src, dst ChainICS02QuerierLightClient) ([]Header, []Header, error) {
-	srcHs, err := sh.SetupHeadersForUpdate(src, dst)
+func (sh syncHeaders) SetupBothHeadersForUpdate(ctx context.Context, src, dst ChainICS02QuerierLightClient) ([]Header, []Header, error) {
+	srcHs, err := sh.SetupHeadersForUpdate(ctx, src, dst)
 	if err != nil {
 		return nil, nil, err
 	}
-	dstHs, err := sh.SetupHeadersForUpdate(dst, src)
+	dstHs, err := sh.SetupHeadersForUpdate(ctx, dst, src)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -122,3 +124,20 @@ func ensureDifferentChains(src, dst ChainInfo) error {
 		return nil
 	}
 }
+
+// setupBothHeadersForUpdate returns the headers to update the clients on both chains with the retry policy of `src`.
+// The headers are synced before each retry, and an error of the sync is returned instead of being retried.
+func setupBothHeadersForUpdate(ctx context.Context, sh SyncHeaders, src, dst *ProvableChain) (srcHeaders, dstHeaders []Header, err error) {
+	var syncErr error
+	err = src.RetryPolicy().Do(ctx, func() error {
+		if syncErr != nil {
+			return retry.Unrecoverable(syncErr)
+		}
+		var err error
+		srcHeaders, dstHeaders, err = sh.SetupBothHeadersForUpdate(ctx, src, dst)
+		return err
+	}, func(n uint, err error) {
+		syncErr = sh.Updates(ctx, src, dst)
+	})
+	return srcHeaders, dstHeaders, err
+}
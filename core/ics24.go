@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	feetypes "github.com/cosmos/ibc-go/v7/modules/apps/29-fee/types"
+	conntypes "github.com/cosmos/ibc-go/v7/modules/core/03-connection/types"
 	host "github.com/cosmos/ibc-go/v7/modules/core/24-host"
 )
 
@@ -29,7 +31,28 @@ func (pe *PathEnd) Vport() error {
 
 // Vversion validates the version identifier in the path
 func (pe *PathEnd) Vversion() error {
-	// TODO: version validation
+	// TODO: validation of application versions
+	metadata, ok, err := parseFeeVersion(pe.Version)
+	if err != nil || !ok {
+		return err
+	}
+	if metadata.FeeVersion != feetypes.Version {
+		return fmt.Errorf("unsupported fee version '%s', expected '%s'", metadata.FeeVersion, feetypes.Version)
+	}
+	if metadata.AppVersion == "" {
+		return fmt.Errorf("fee-wrapped version must specify the application version")
+	}
+	return nil
+}
+
+// VconnVersion validates the connection version in the path
+func (pe *PathEnd) VconnVersion() error {
+	if v := pe.GetConnectionVersion(); v != nil {
+		return conntypes.ValidateVersion(v)
+	}
+	if len(pe.ConnectionFeatures) > 0 {
+		return fmt.Errorf("connection features are set without connection version")
+	}
 	return nil
 }
 
@@ -52,6 +75,9 @@ func (pe *PathEnd) Validate() error {
 	if err := pe.Vport(); err != nil {
 		return err
 	}
+	if err := pe.VconnVersion(); err != nil {
+		return err
+	}
 	if !(strings.ToUpper(pe.Order) == "ORDERED" || strings.ToUpper(pe.Order) == "UNORDERED") {
 		return fmt.Errorf("channel must be either 'ORDERED' or 'UNORDERED' is '%s'", pe.Order)
 	}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"context"
+
+	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
+)
+
+// InFlightTx is a relay transaction that was broadcast but not confirmed to be included in a block.
+// Its packets are not relayed again while it is in the mempool, even after a restart, so that no redundant transactions are paid for.
+type InFlightTx struct {
+	TxHash   string   `json:"tx_hash"`
+	Recvs    []uint64 `json:"recvs,omitempty"`    // sequences of the packets received by the transaction
+	Acks     []uint64 `json:"acks,omitempty"`     // sequences of the packets acknowledged by the transaction
+	Timeouts []uint64 `json:"timeouts,omitempty"` // sequences of the packets timed out by the transaction
+}
+
+// TxStatusQuerier is an optional interface of Chain that queries the status of a transaction
+type TxStatusQuerier interface {
+	// QueryTxStatus returns the result of the transaction if it is included in a block.
+	// Otherwise the result is nil and `pending` reports whether the transaction is still in the mempool.
+	QueryTxStatus(ctx context.Context, txHash string) (res *TxResult, pending bool, err error)
+}
+
+// newInFlightTx returns the in-flight transaction of the batch, or nil if the batch was included in a block or rejected
+func newInFlightTx(res *BatchResult) *InFlightTx {
+	if res.Err == nil || res.Result == nil || res.Result.Height != 0 || res.Result.Code != 0 {
+		return nil
+	}
+	tx := &InFlightTx{TxHash: res.Result.TxHash}
+	for _, msg := range res.Msgs {
+		switch msg := msg.(type) {
+		case *chantypes.MsgRecvPacket:
+			tx.Recvs = append(tx.Recvs, msg.Packet.Sequence)
+		case *chantypes.MsgAcknowledgement:
+			tx.Acks = append(tx.Acks, msg.Packet.Sequence)
+		case *chantypes.MsgTimeout:
+			tx.Timeouts = append(tx.Timeouts, msg.Packet.Sequence)
+		case *chantypes.MsgTimeoutOnClose:
+			tx.Timeouts = append(tx.Timeouts, msg.Packet.Sequence)
+		}
+	}
+	if len(tx.Recvs) == 0 && len(tx.Acks) == 0 && len(tx.Timeouts) == 0 {
+		return nil
+	}
+	return tx
+}
+
+// saveInFlightTxs persists the transactions of `results` that were broadcast to `chain` but not confirmed to be included in a block
+func saveInFlightTxs(store *CheckpointStore, chain *ProvableChain, results []*BatchResult) error {
+	if store == nil {
+		return nil
+	}
+	for _, res := range results {
+		tx := newInFlightTx(res)
+		if tx == nil {
+			continue
+		}
+		GetChainLogger(chain).Info("saving the transaction that is not confirmed yet", "tx_hash", tx.TxHash)
+		if err := store.saveTx(chain.Path(), tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryInFlightTxs returns the in-flight transactions submitted to `chain` that are still in the mempool.
+// The ones that have been included in a block or dropped from the mempool are deleted from the store.
+func queryInFlightTxs(ctx context.Context, store *CheckpointStore, chain *ProvableChain) ([]*InFlightTx, error) {
+	if store == nil {
+		return nil, nil
+	}
+	txs, err := store.loadTxs(chain.Path())
+	if err != nil {
+		return nil, err
+	}
+	q, ok := chain.Chain.(TxStatusQuerier)
+	logger := GetChainLogger(chain)
+	var pending []*InFlightTx
+	for hash, tx := range txs {
+		if ok {
+			res, inMempool, err := q.QueryTxStatus(ctx, hash)
+			if err != nil {
+				return nil, err
+			} else if inMempool {
+				pending = append(pending, tx)
+				continue
+			} else if res != nil {
+				logger.Info("in-flight transaction was included", "tx_hash", hash, "height", res.Height, "code", res.Code)
+			} else {
+				logger.Info("in-flight transaction was dropped from the mempool", "tx_hash", hash)
+			}
+		}
+		if err := store.deleteTx(chain.Path(), hash); err != nil {
+			return nil, err
+		}
+	}
+	return pending, nil
+}
+
+// excludeInFlight returns the packets on `chain` whose sequences are not in any of `seqs` of the in-flight transactions
+func excludeInFlight(chain *ProvableChain, packets PacketInfoList, txs []*InFlightTx, seqs func(*InFlightTx) []uint64) PacketInfoList {
+	inFlight := make(map[uint64]struct{})
+	for _, tx := range txs {
+		for _, seq := range seqs(tx) {
+			inFlight[seq] = struct{}{}
+		}
+	}
+	if len(inFlight) == 0 {
+		return packets
+	}
+	var ret PacketInfoList
+	for _, p := range packets {
+		if _, ok := inFlight[p.Sequence]; !ok {
+			ret = append(ret, p)
+		}
+	}
+	if num := len(packets) - len(ret); num > 0 {
+		GetChainLogger(chain).Info("packets are skipped because they are relayed by in-flight transactions", "count", num)
+	}
+	return ret
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"log/slog"
+
+	"github.com/hyperledger-labs/yui-relayer/log"
+)
+
+// WithChain returns the logger with the ID of the chain and the identifiers of its path end
+func WithChain(logger *slog.Logger, chain Chain) *slog.Logger {
+	logger = logger.With("chain_id", chain.ChainID())
+	if pe := chain.Path(); pe != nil {
+		logger = logger.With(
+			"client_id", pe.ClientID,
+			"connection_id", pe.ConnectionID,
+			"port_id", pe.PortID,
+			"channel_id", pe.ChannelID,
+		)
+	}
+	return logger
+}
+
+// GetChainLogger returns the logger for the events of the chain
+func GetChainLogger(chain Chain) *slog.Logger {
+	return WithChain(log.GetLogger(), chain)
+}
+
+// GetChainPairLogger returns the logger for the events between src and dst.
+// The identifiers of both path ends are grouped under "path".
+func GetChainPairLogger(src, dst Chain) *slog.Logger {
+	return log.GetLogger().With(slog.Group("path", pathEndAttr("src", src), pathEndAttr("dst", dst)))
+}
+
+func pathEndAttr(key string, chain Chain) slog.Attr {
+	pe := chain.Path()
+	if pe == nil {
+		return slog.Group(key, "chain_id", chain.ChainID())
+	}
+	return slog.Group(key,
+		"chain_id", chain.ChainID(),
+		"client_id", pe.ClientID,
+		"connection_id", pe.ConnectionID,
+		"port_id", pe.PortID,
+		"channel_id", pe.ChannelID,
+	)
+}
@@ -0,0 +1,225 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsNamespace = "relayer"
+
+// channelLabels identify a channel end and its counterparty. The direction of a metric is from `chain_id` to `counterparty_chain_id`.
+var channelLabels = []string{"chain_id", "channel_id", "counterparty_chain_id", "counterparty_channel_id"}
+
+// relayMetrics are the Prometheus metrics of the relayer
+type relayMetrics struct {
+	registry *prometheus.Registry
+
+	unrelayedPackets *prometheus.GaugeVec
+	unrelayedAcks    *prometheus.GaugeVec
+	relayedPackets   *prometheus.CounterVec
+	relayedAcks      *prometheus.CounterVec
+	timedOutPackets  *prometheus.CounterVec
+	txs              *prometheus.CounterVec
+	gasUsed          *prometheus.CounterVec
+	feesSpent        *prometheus.CounterVec
+	clientUpdates    *prometheus.CounterVec
+	clientExpiresIn  *prometheus.GaugeVec
+	finalizedHeight  *prometheus.GaugeVec
+	relayDuration    *prometheus.HistogramVec
+}
+
+var metrics = newRelayMetrics()
+
+func newRelayMetrics() *relayMetrics {
+	m := &relayMetrics{
+		registry: prometheus.NewRegistry(),
+		unrelayedPackets: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "unrelayed_packets",
+			Help:      "Number of packets sent from the channel end that are not received by the counterparty yet",
+		}, channelLabels),
+		unrelayedAcks: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "unrelayed_acks",
+			Help:      "Number of acknowledgements written on the channel end that are not relayed to the counterparty yet",
+		}, channelLabels),
+		relayedPackets: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "relayed_packets_total",
+			Help:      "Number of packets sent from the channel end and relayed to the counterparty",
+		}, channelLabels),
+		relayedAcks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "relayed_acks_total",
+			Help:      "Number of acknowledgements written on the channel end and relayed to the counterparty",
+		}, channelLabels),
+		timedOutPackets: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "timed_out_packets_total",
+			Help:      "Number of packets sent from the channel end and timed out",
+		}, channelLabels),
+		txs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "txs_total",
+			Help:      "Number of transactions submitted to the chain by result and codespace",
+		}, append(channelLabels, "result", "codespace")),
+		gasUsed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "gas_used_total",
+			Help:      "Gas used by the transactions submitted to the chain",
+		}, channelLabels),
+		feesSpent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "fees_spent_total",
+			Help:      "Fees paid for the transactions submitted to the chain",
+		}, append(channelLabels, "denom")),
+		clientUpdates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "client_updates_total",
+			Help:      "Number of client updates submitted to the chain",
+		}, []string{"chain_id", "client_id"}),
+		clientExpiresIn: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "client_expires_in_seconds",
+			Help:      "Time left until the trusting period of the client on the chain elapses, which is negative once the client has expired",
+		}, []string{"chain_id", "client_id"}),
+		finalizedHeight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "latest_finalized_height",
+			Help:      "Latest finalized height of the chain seen by the relay service",
+		}, []string{"chain_id"}),
+		relayDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "relay_duration_seconds",
+			Help:      "Duration of a relay loop of the relay service from the chain to the counterparty",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+		}, channelLabels),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.unrelayedPackets,
+		m.unrelayedAcks,
+		m.relayedPackets,
+		m.relayedAcks,
+		m.timedOutPackets,
+		m.txs,
+		m.gasUsed,
+		m.feesSpent,
+		m.clientUpdates,
+		m.clientExpiresIn,
+		m.finalizedHeight,
+		m.relayDuration,
+	)
+	return m
+}
+
+// MetricsHandler returns the HTTP handler that exposes the metrics of the relayer in the Prometheus format
+func MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(metrics.registry, promhttp.HandlerOpts{})
+}
+
+// ServeMetrics starts serving the metrics of the relayer on `addr` at /metrics in the background until `ctx` is done.
+// It returns an error if it fails to listen on `addr`.
+func ServeMetrics(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s for metrics: %w", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", MetricsHandler())
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	go func() {
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.GetLogger().Error("metrics server stopped", "error", err)
+		}
+	}()
+	return nil
+}
+
+func channelLabelValues(chain, counterparty Chain) []string {
+	return []string{chain.ChainID(), chain.Path().ChannelID, counterparty.ChainID(), counterparty.Path().ChannelID}
+}
+
+// observeRelay records the backlog of the path and the duration of a relay loop of the service
+func (m *relayMetrics) observeRelay(src, dst Chain, packets, acks *RelayPackets, duration time.Duration) {
+	srcLabels, dstLabels := channelLabelValues(src, dst), channelLabelValues(dst, src)
+	if packets != nil {
+		srcBacklog, dstBacklog := packets.Backlog()
+		m.unrelayedPackets.WithLabelValues(srcLabels...).Set(float64(srcBacklog))
+		m.unrelayedPackets.WithLabelValues(dstLabels...).Set(float64(dstBacklog))
+	}
+	if acks != nil {
+		srcBacklog, dstBacklog := acks.Backlog()
+		m.unrelayedAcks.WithLabelValues(srcLabels...).Set(float64(srcBacklog))
+		m.unrelayedAcks.WithLabelValues(dstLabels...).Set(float64(dstBacklog))
+	}
+	m.relayDuration.WithLabelValues(srcLabels...).Observe(duration.Seconds())
+}
+
+// observeFinalizedHeights records the latest finalized heights of the chains
+func (m *relayMetrics) observeFinalizedHeights(sh SyncHeaders, chains ...Chain) {
+	for _, chain := range chains {
+		if h := sh.GetLatestFinalizedHeader(chain.ChainID()); h != nil {
+			m.finalizedHeight.WithLabelValues(chain.ChainID()).Set(float64(h.GetHeight().GetRevisionHeight()))
+		}
+	}
+}
+
+// observeClientExpiry records the time left until the client on `chain` expires
+func (m *relayMetrics) observeClientExpiry(chain Chain, expiresIn time.Duration) {
+	m.clientExpiresIn.WithLabelValues(chain.ChainID(), chain.Path().ClientID).Set(expiresIn.Seconds())
+}
+
+// observeBatches records the transactions submitted to `chain` and the relays performed by them
+func (m *relayMetrics) observeBatches(chain, counterparty Chain, results []*BatchResult) {
+	labels := channelLabelValues(chain, counterparty)
+	counterpartyLabels := channelLabelValues(counterparty, chain)
+	for _, res := range results {
+		if res.Result != nil {
+			m.gasUsed.WithLabelValues(labels...).Add(float64(res.Result.GasUsed))
+			for _, fee := range res.Result.Fee {
+				amount, _ := new(big.Float).SetInt(fee.Amount.BigInt()).Float64()
+				m.feesSpent.WithLabelValues(append(labels, fee.Denom)...).Add(amount)
+			}
+		}
+		if res.Err != nil {
+			codespace := ""
+			if res.Result != nil {
+				codespace = res.Result.Codespace
+			}
+			m.txs.WithLabelValues(append(labels, "failure", codespace)...).Inc()
+			continue
+		}
+		m.txs.WithLabelValues(append(labels, "success", "")...).Inc()
+
+		for _, msg := range res.Msgs {
+			switch msg := msg.(type) {
+			case *chantypes.MsgRecvPacket:
+				m.relayedPackets.WithLabelValues(counterpartyLabels...).Inc()
+			case *chantypes.MsgAcknowledgement:
+				m.relayedAcks.WithLabelValues(counterpartyLabels...).Inc()
+			case *chantypes.MsgTimeout, *chantypes.MsgTimeoutOnClose:
+				m.timedOutPackets.WithLabelValues(labels...).Inc()
+			case *clienttypes.MsgUpdateClient:
+				m.clientUpdates.WithLabelValues(chain.ChainID(), msg.ClientId).Inc()
+			}
+		}
+	}
+}
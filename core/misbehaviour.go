@@ -0,0 +1,116 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v7/modules/core/exported"
+)
+
+// ClientUpdateQuerier is an optional interface of Chain that queries the headers submitted to the client of the path end
+type ClientUpdateQuerier interface {
+	// QueryClientUpdateHeaders returns the headers submitted to the client in the blocks from `fromHeight` to the height of `ctx`
+	// in ascending order of the block heights
+	QueryClientUpdateHeaders(ctx QueryContext, fromHeight uint64) ([]*ClientUpdate, error)
+}
+
+// ClientUpdate is a header submitted to a client
+type ClientUpdate struct {
+	Height uint64 // height of the block that includes the update
+	Header Header
+}
+
+// MisbehaviourChecker is an optional interface of Prover that detects misbehaviours of the chain
+type MisbehaviourChecker interface {
+	// CheckMisbehaviour checks if a header submitted to the client `clientID` of this chain on the counterparty chain
+	// conflicts with the header of this chain at the same height. It returns a misbehaviour if they conflict, or nil otherwise.
+	CheckMisbehaviour(ctx context.Context, clientID string, header Header) (ibcexported.ClientMessage, error)
+}
+
+// checkMisbehaviours checks the client updates on both chains since the last check
+func (srv *RelayService) checkMisbehaviours(ctx context.Context) error {
+	if !srv.misbehaviourDetection {
+		return nil
+	}
+	if err := srv.checkMisbehaviour(ctx, srv.src, srv.dst); err != nil {
+		return err
+	}
+	return srv.checkMisbehaviour(ctx, srv.dst, srv.src)
+}
+
+// checkMisbehaviour checks the headers submitted to the client of `chain` on `counterparty`,
+// and submits a misbehaviour to `counterparty` to freeze the client if any of them conflicts with `chain`
+func (srv *RelayService) checkMisbehaviour(ctx context.Context, chain, counterparty *ProvableChain) error {
+	checker, ok := chain.Prover.(MisbehaviourChecker)
+	if !ok {
+		return fmt.Errorf("prover of chain %s does not support misbehaviour detection", chain.ChainID())
+	}
+	querier, ok := counterparty.Chain.(ClientUpdateQuerier)
+	if !ok {
+		return fmt.Errorf("chain %s does not support client update queries", counterparty.ChainID())
+	}
+
+	latest, err := counterparty.LatestHeight(ctx)
+	if err != nil {
+		return err
+	}
+	// the check starts from the latest height when the service starts
+	from, ok := srv.misbehaviourScanHeights[counterparty.ChainID()]
+	if !ok {
+		from = latest.GetRevisionHeight()
+	}
+	if from > latest.GetRevisionHeight() {
+		return nil
+	}
+
+	clientID := counterparty.Path().ClientID
+	updates, err := querier.QueryClientUpdateHeaders(NewQueryContext(ctx, latest), from)
+	if err != nil {
+		return err
+	}
+	// the scan height is advanced only past the updates that have been checked, so that a failed one is checked again in the next round
+	next := latest.GetRevisionHeight() + 1
+	defer func() {
+		srv.misbehaviourScanHeights[counterparty.ChainID()] = next
+	}()
+	for _, update := range updates {
+		header := update.Header
+		misbehaviour, err := checker.CheckMisbehaviour(ctx, clientID, header)
+		if err != nil {
+			GetChainLogger(counterparty).Error("failed to check the header", "height", header.GetHeight(), "update_height", update.Height, "error", err)
+			if update.Height < next {
+				next = update.Height
+			}
+			continue
+		} else if misbehaviour == nil {
+			continue
+		}
+		GetChainLogger(counterparty).Warn("misbehaviour detected", "misbehaving_chain_id", chain.ChainID(), "height", header.GetHeight())
+		if err := submitMisbehaviour(ctx, counterparty, clientID, misbehaviour); err != nil {
+			GetChainLogger(counterparty).Error("failed to submit the misbehaviour", "height", header.GetHeight(), "error", err)
+			if update.Height < next {
+				next = update.Height
+			}
+			return fmt.Errorf("failed to submit the misbehaviour of chain %s to client %s on chain %s: %w", chain.ChainID(), clientID, counterparty.ChainID(), err)
+		}
+		GetChainLogger(counterparty).Info("misbehaviour submitted, the client is frozen")
+	}
+	return nil
+}
+
+// submitMisbehaviour submits the misbehaviour to the client on the chain
+func submitMisbehaviour(ctx context.Context, chain *ProvableChain, clientID string, misbehaviour ibcexported.ClientMessage) error {
+	signer, err := chain.GetAddress()
+	if err != nil {
+		return err
+	}
+	msg, err := clienttypes.NewMsgSubmitMisbehaviour(clientID, misbehaviour, signer.String())
+	if err != nil {
+		return err
+	}
+	// a broadcast is not interrupted halfway so that the sequence of the signer stays consistent
+	_, err = chain.Send(context.WithoutCancel(ctx), []sdk.Msg{msg})
+	return err
+}
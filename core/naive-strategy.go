@@ -2,28 +2,77 @@ package core
 
 import (
 	"context"
-	"log"
+	"fmt"
 
-	retry "github.com/avast/retry-go"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
 	host "github.com/cosmos/ibc-go/v7/modules/core/24-host"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"golang.org/x/sync/errgroup"
 )
 
 // NaiveStrategy is an implementation of Strategy.
 type NaiveStrategy struct {
 	Ordered      bool
-	MaxTxSize    uint64 // maximum permitted size of the msgs in a bundled relay transaction
-	MaxMsgLength uint64 // maximum amount of messages in a bundled relay transaction
+	MaxTxSize    uint64        // maximum permitted size of the msgs in a bundled relay transaction
+	MaxMsgLength uint64        // maximum amount of messages in a bundled relay transaction
+	Filter       *PacketFilter // packets that don't pass the filter are not relayed
+	MinIncentive sdk.Coins     // packets with ICS-29 fees below this are not relayed if set
+	// MaxUnrelayedPackets is the maximum number of packets (and acknowledgements) relayed per iteration, oldest first.
+	// Only the oldest sequences are searched for, and the remaining backlog is relayed in later iterations. There is no limit if zero.
+	MaxUnrelayedPackets uint64
+
+	// Checkpoints is the store of the relay progress, which makes the strategy query only the packets since the last relay if set
+	Checkpoints *CheckpointStore
 }
 
 var _ StrategyI = (*NaiveStrategy)(nil)
+var _ CheckpointStrategy = (*NaiveStrategy)(nil)
 
 func NewNaiveStrategy() *NaiveStrategy {
 	return &NaiveStrategy{}
 }
 
+// NaiveStrategyOptions is the options of NaiveStrategy in StrategyCfg
+type NaiveStrategyOptions struct {
+	MaxTxSize    uint64 `json:"max-tx-size,omitempty"`
+	MaxMsgLength uint64 `json:"max-msg-length,omitempty"`
+	// MinIncentive is the minimum ICS-29 fee (e.g. "100stake") for a packet to be relayed. All packets are relayed if empty.
+	MinIncentive string `json:"min-incentive,omitempty"`
+	// MaxUnrelayedPackets is the maximum number of packets relayed in each direction per iteration. There is no limit if zero.
+	MaxUnrelayedPackets uint64 `json:"max-unrelayed-packets,omitempty"`
+}
+
+// newNaiveStrategy is a StrategyBuilder of NaiveStrategy
+func newNaiveStrategy(path *Path) (StrategyI, error) {
+	var opts NaiveStrategyOptions
+	if err := path.Strategy.UnmarshalOptions(&opts); err != nil {
+		return nil, err
+	}
+	st := NewNaiveStrategy()
+	st.MaxTxSize = opts.MaxTxSize
+	st.MaxMsgLength = opts.MaxMsgLength
+	st.MaxUnrelayedPackets = opts.MaxUnrelayedPackets
+	if opts.MinIncentive != "" {
+		coins, err := sdk.ParseCoinsNormalized(opts.MinIncentive)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min-incentive '%s': %w", opts.MinIncentive, err)
+		}
+		st.MinIncentive = coins
+	}
+	if path.Src != nil {
+		st.Ordered = path.Ordered()
+	}
+	st.Filter = path.Filter
+	return st, nil
+}
+
+// SetCheckpointStore implements CheckpointStrategy
+func (st *NaiveStrategy) SetCheckpointStore(store *CheckpointStore) {
+	st.Checkpoints = store
+}
+
 // GetType implements Strategy
 func (st NaiveStrategy) GetType() string {
 	return "naive"
@@ -39,50 +88,69 @@ func (st NaiveStrategy) SetupRelay(ctx context.Context, src, dst *ProvableChain)
 	return nil
 }
 
-func (st NaiveStrategy) UnrelayedPackets(src, dst *ProvableChain, sh SyncHeaders) (*RelayPackets, error) {
+func (st NaiveStrategy) UnrelayedPackets(ctx context.Context, src, dst *ProvableChain, sh SyncHeaders) (*RelayPackets, error) {
 	var (
-		eg         = new(errgroup.Group)
-		srcPackets PacketInfoList
-		dstPackets PacketInfoList
+		eg                     = new(errgroup.Group)
+		srcPackets, dstPackets PacketInfoList
+		srcBacklog, dstBacklog int
 	)
 
-	srcCtx := sh.GetQueryContext(src.ChainID())
-	dstCtx := sh.GetQueryContext(dst.ChainID())
+	srcCtx := sh.GetQueryContext(ctx, src.ChainID())
+	dstCtx := sh.GetQueryContext(ctx, dst.ChainID())
 
 	var srcLatestCtx, dstLatestCtx QueryContext
-	if srcHeight, err := src.LatestHeight(); err != nil {
+	if srcHeight, err := src.LatestHeight(ctx); err != nil {
 		return nil, err
-	} else if dstHeight, err := dst.LatestHeight(); err != nil {
+	} else if dstHeight, err := dst.LatestHeight(ctx); err != nil {
 		return nil, err
 	} else {
-		srcLatestCtx = NewQueryContext(context.TODO(), srcHeight)
-		dstLatestCtx = NewQueryContext(context.TODO(), dstHeight)
+		srcLatestCtx = NewQueryContext(ctx, srcHeight)
+		dstLatestCtx = NewQueryContext(ctx, dstHeight)
 	}
 
 	eg.Go(func() error {
-		return retry.Do(func() error {
+		return src.RetryPolicy().Do(ctx, func() error {
 			var err error
-			srcPackets, err = src.QueryUnfinalizedRelayPackets(srcCtx, dst)
+			srcPackets, srcBacklog, err = queryUnfinalizedRelayPackets(srcCtx, st.Checkpoints, src, dst, st.MaxUnrelayedPackets)
 			return err
-		}, rtyAtt, rtyDel, rtyErr, retry.OnRetry(func(n uint, err error) {
-			log.Printf("- [%s]@{%d} - try(%d/%d) query unfinalized packets: %s", src.ChainID(), srcCtx.Height().GetRevisionHeight(), n+1, rtyAttNum, err)
-		}))
+		}, func(n uint, err error) {
+			GetChainLogger(src).Info("retrying to query unfinalized packets", "height", srcCtx.Height().GetRevisionHeight(), "attempt", n+1, "max_attempts", src.RetryPolicy().GetAttempts(), "error", err)
+		})
 	})
 
 	eg.Go(func() error {
-		return retry.Do(func() error {
+		return dst.RetryPolicy().Do(ctx, func() error {
 			var err error
-			dstPackets, err = dst.QueryUnfinalizedRelayPackets(dstCtx, src)
+			dstPackets, dstBacklog, err = queryUnfinalizedRelayPackets(dstCtx, st.Checkpoints, dst, src, st.MaxUnrelayedPackets)
 			return err
-		}, rtyAtt, rtyDel, rtyErr, retry.OnRetry(func(n uint, err error) {
-			log.Printf("- [%s]@{%d} - try(%d/%d) query unfinalized packets: %s", dst.ChainID(), dstCtx.Height().GetRevisionHeight(), n+1, rtyAttNum, err)
-		}))
+		}, func(n uint, err error) {
+			GetChainLogger(dst).Info("retrying to query unfinalized packets", "height", dstCtx.Height().GetRevisionHeight(), "attempt", n+1, "max_attempts", dst.RetryPolicy().GetAttempts(), "error", err)
+		})
 	})
 
 	if err := eg.Wait(); err != nil {
 		return nil, err
 	}
 
+	// the packets are received on the counterparty, or timed out on their source chain
+	srcTxs, dstTxs, err := st.queryInFlightTxs(ctx, src, dst)
+	if err != nil {
+		return nil, err
+	}
+	srcPackets = excludeInFlight(src, srcPackets, dstTxs, func(tx *InFlightTx) []uint64 { return tx.Recvs })
+	srcPackets = excludeInFlight(src, srcPackets, srcTxs, func(tx *InFlightTx) []uint64 { return tx.Timeouts })
+	dstPackets = excludeInFlight(dst, dstPackets, srcTxs, func(tx *InFlightTx) []uint64 { return tx.Recvs })
+	dstPackets = excludeInFlight(dst, dstPackets, dstTxs, func(tx *InFlightTx) []uint64 { return tx.Timeouts })
+
+	srcPackets, err = st.applyFilter(src, srcPackets)
+	if err != nil {
+		return nil, err
+	}
+	dstPackets, err = st.applyFilter(dst, dstPackets)
+	if err != nil {
+		return nil, err
+	}
+
 	eg.Go(func() error {
 		seqs, err := dst.QueryUnreceivedPackets(dstLatestCtx, srcPackets.ExtractSequenceList())
 		if err != nil {
@@ -105,13 +173,45 @@ func (st NaiveStrategy) UnrelayedPackets(src, dst *ProvableChain, sh SyncHeaders
 		return nil, err
 	}
 
+	// a packet has to be timed out on its source chain if it can no longer be received on the counterparty
+	eg.Go(func() error {
+		return checkPacketTimeouts(dstCtx, dst, srcPackets)
+	})
+
+	eg.Go(func() error {
+		return checkPacketTimeouts(srcCtx, src, dstPackets)
+	})
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	// the fees of a packet are escrowed on its source chain
+	eg.Go(func() error {
+		var err error
+		srcPackets, err = filterByIncentive(srcLatestCtx, src, srcPackets, recvFee, st.MinIncentive)
+		return err
+	})
+
+	eg.Go(func() error {
+		var err error
+		dstPackets, err = filterByIncentive(dstLatestCtx, dst, dstPackets, recvFee, st.MinIncentive)
+		return err
+	})
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
 	return &RelayPackets{
-		Src: srcPackets,
-		Dst: dstPackets,
+		Src:        srcPackets,
+		Dst:        dstPackets,
+		SrcBacklog: srcBacklog,
+		DstBacklog: dstBacklog,
 	}, nil
 }
 
-func (st NaiveStrategy) RelayPackets(src, dst *ProvableChain, sp *RelayPackets, sh SyncHeaders) error {
+func (st NaiveStrategy) RelayPackets(ctx context.Context, src, dst *ProvableChain, sp *RelayPackets, sh SyncHeaders) error {
 	// set the maximum relay transaction constraints
 	msgs := &RelayMsgs{
 		Src:          []sdk.Msg{},
@@ -120,8 +220,8 @@ func (st NaiveStrategy) RelayPackets(src, dst *ProvableChain, sp *RelayPackets,
 		MaxMsgLength: st.MaxMsgLength,
 	}
 
-	srcCtx := sh.GetQueryContext(src.ChainID())
-	dstCtx := sh.GetQueryContext(dst.ChainID())
+	srcCtx := sh.GetQueryContext(ctx, src.ChainID())
+	dstCtx := sh.GetQueryContext(ctx, dst.ChainID())
 	srcAddress, err := src.GetAddress()
 	if err != nil {
 		return err
@@ -131,8 +231,12 @@ func (st NaiveStrategy) RelayPackets(src, dst *ProvableChain, sp *RelayPackets,
 		return err
 	}
 
-	if len(sp.Src) > 0 {
-		hs, err := sh.SetupHeadersForUpdate(src, dst)
+	srcRecvs, srcTimeouts := sp.Src.SplitByTimeout()
+	dstRecvs, dstTimeouts := sp.Dst.SplitByTimeout()
+
+	// the client on dst must be updated to verify both receipts of src packets and timeouts of dst packets
+	if len(srcRecvs) > 0 || len(dstTimeouts) > 0 {
+		hs, err := setupHeadersForUpdate(ctx, sh, src, dst)
 		if err != nil {
 			return err
 		}
@@ -141,8 +245,8 @@ func (st NaiveStrategy) RelayPackets(src, dst *ProvableChain, sp *RelayPackets,
 		}
 	}
 
-	if len(sp.Dst) > 0 {
-		hs, err := sh.SetupHeadersForUpdate(dst, src)
+	if len(dstRecvs) > 0 || len(srcTimeouts) > 0 {
+		hs, err := setupHeadersForUpdate(ctx, sh, dst, src)
 		if err != nil {
 			return err
 		}
@@ -151,83 +255,120 @@ func (st NaiveStrategy) RelayPackets(src, dst *ProvableChain, sp *RelayPackets,
 		}
 	}
 
-	packetsForDst, err := collectPackets(srcCtx, src, sp.Src, dstAddress)
+	packetsForDst, err := collectPackets(srcCtx, src, srcRecvs, dstAddress)
+	if err != nil {
+		return err
+	}
+	packetsForSrc, err := collectPackets(dstCtx, dst, dstRecvs, srcAddress)
 	if err != nil {
 		return err
 	}
-	packetsForSrc, err := collectPackets(dstCtx, dst, sp.Dst, srcAddress)
+	timeoutsForSrc, err := collectTimeouts(dstCtx, dst, srcTimeouts, srcAddress)
+	if err != nil {
+		return err
+	}
+	timeoutsForDst, err := collectTimeouts(srcCtx, src, dstTimeouts, dstAddress)
 	if err != nil {
 		return err
 	}
 
-	if len(packetsForDst) == 0 && len(packetsForSrc) == 0 {
-		log.Printf("- No packets to relay between [%s]port{%s} and [%s]port{%s}",
-			src.ChainID(), src.Path().PortID, dst.ChainID(), dst.Path().PortID)
+	if len(packetsForDst) == 0 && len(packetsForSrc) == 0 && len(timeoutsForSrc) == 0 && len(timeoutsForDst) == 0 {
+		GetChainPairLogger(src, dst).Info("no packets to relay")
 		return nil
 	}
 
 	msgs.Dst = append(msgs.Dst, packetsForDst...)
+	msgs.Dst = append(msgs.Dst, timeoutsForDst...)
 	msgs.Src = append(msgs.Src, packetsForSrc...)
+	msgs.Src = append(msgs.Src, timeoutsForSrc...)
 
 	// send messages to their respective chains
-	if msgs.Send(src, dst); msgs.Success() {
-		if num := len(packetsForDst); num > 0 {
-			logPacketsRelayed(dst, src, num)
+	sendRelayMsgs(ctx, msgs, src, dst)
+	if err := st.saveInFlightTxs(src, dst, msgs); err != nil {
+		return err
+	}
+	// nothing is relayed in a dry run
+	if msgs.Success() && !src.IsDryRun() {
+		if len(packetsForDst) > 0 {
+			logPacketsRelayed(dst, src, packetsForDst)
+		}
+		if len(packetsForSrc) > 0 {
+			logPacketsRelayed(src, dst, packetsForSrc)
+		}
+		if len(timeoutsForSrc) > 0 {
+			logPacketsTimedOut(src, dst, timeoutsForSrc)
 		}
-		if num := len(packetsForSrc); num > 0 {
-			logPacketsRelayed(src, dst, num)
+		if len(timeoutsForDst) > 0 {
+			logPacketsTimedOut(dst, src, timeoutsForDst)
 		}
 	}
 
 	return nil
 }
 
-func (st NaiveStrategy) UnrelayedAcknowledgements(src, dst *ProvableChain, sh SyncHeaders) (*RelayPackets, error) {
+func (st NaiveStrategy) UnrelayedAcknowledgements(ctx context.Context, src, dst *ProvableChain, sh SyncHeaders) (*RelayPackets, error) {
 	var (
-		eg      = new(errgroup.Group)
-		srcAcks PacketInfoList
-		dstAcks PacketInfoList
+		eg                     = new(errgroup.Group)
+		srcAcks, dstAcks       PacketInfoList
+		srcBacklog, dstBacklog int
 	)
 
-	srcCtx := sh.GetQueryContext(src.ChainID())
-	dstCtx := sh.GetQueryContext(dst.ChainID())
+	srcCtx := sh.GetQueryContext(ctx, src.ChainID())
+	dstCtx := sh.GetQueryContext(ctx, dst.ChainID())
 
 	var srcCtxLatest, dstCtxLatest QueryContext
-	if srcHeight, err := src.LatestHeight(); err != nil {
+	if srcHeight, err := src.LatestHeight(ctx); err != nil {
 		return nil, err
-	} else if dstHeight, err := dst.LatestHeight(); err != nil {
+	} else if dstHeight, err := dst.LatestHeight(ctx); err != nil {
 		return nil, err
 	} else {
-		srcCtxLatest = NewQueryContext(context.TODO(), srcHeight)
-		dstCtxLatest = NewQueryContext(context.TODO(), dstHeight)
+		srcCtxLatest = NewQueryContext(ctx, srcHeight)
+		dstCtxLatest = NewQueryContext(ctx, dstHeight)
 	}
 
 	eg.Go(func() error {
-		return retry.Do(func() error {
+		return src.RetryPolicy().Do(ctx, func() error {
 			var err error
-			srcAcks, err = src.QueryUnfinalizedRelayAcknowledgements(srcCtx, dst)
+			srcAcks, srcBacklog, err = queryUnfinalizedRelayAcknowledgements(srcCtx, st.Checkpoints, src, dst, st.MaxUnrelayedPackets)
 			return err
-		}, rtyAtt, rtyDel, rtyErr, retry.OnRetry(func(n uint, err error) {
-			log.Printf("- [%s]@{%d} - try(%d/%d) query packet acknowledgements: %s", src.ChainID(), srcCtx.Height().GetRevisionHeight(), n+1, rtyAttNum, err)
-			sh.Updates(src, dst)
-		}))
+		}, func(n uint, err error) {
+			GetChainLogger(src).Info("retrying to query packet acknowledgements", "height", srcCtx.Height().GetRevisionHeight(), "attempt", n+1, "max_attempts", src.RetryPolicy().GetAttempts(), "error", err)
+			sh.Updates(ctx, src, dst)
+		})
 	})
 
 	eg.Go(func() error {
-		return retry.Do(func() error {
+		return dst.RetryPolicy().Do(ctx, func() error {
 			var err error
-			dstAcks, err = dst.QueryUnfinalizedRelayAcknowledgements(dstCtx, src)
+			dstAcks, dstBacklog, err = queryUnfinalizedRelayAcknowledgements(dstCtx, st.Checkpoints, dst, src, st.MaxUnrelayedPackets)
 			return err
-		}, rtyAtt, rtyDel, rtyErr, retry.OnRetry(func(n uint, err error) {
-			log.Printf("- [%s]@{%d} - try(%d/%d) query packet acknowledgements: %s", dst.ChainID(), dstCtx.Height().GetRevisionHeight(), n+1, rtyAttNum, err)
-			sh.Updates(src, dst)
-		}))
+		}, func(n uint, err error) {
+			GetChainLogger(dst).Info("retrying to query packet acknowledgements", "height", dstCtx.Height().GetRevisionHeight(), "attempt", n+1, "max_attempts", dst.RetryPolicy().GetAttempts(), "error", err)
+			sh.Updates(ctx, src, dst)
+		})
 	})
 
 	if err := eg.Wait(); err != nil {
 		return nil, err
 	}
 
+	// the acknowledgements written on a chain are relayed to its counterparty
+	srcTxs, dstTxs, err := st.queryInFlightTxs(ctx, src, dst)
+	if err != nil {
+		return nil, err
+	}
+	srcAcks = excludeInFlight(src, srcAcks, dstTxs, func(tx *InFlightTx) []uint64 { return tx.Acks })
+	dstAcks = excludeInFlight(dst, dstAcks, srcTxs, func(tx *InFlightTx) []uint64 { return tx.Acks })
+
+	srcAcks, err = st.applyFilter(src, srcAcks)
+	if err != nil {
+		return nil, err
+	}
+	dstAcks, err = st.applyFilter(dst, dstAcks)
+	if err != nil {
+		return nil, err
+	}
+
 	eg.Go(func() error {
 		seqs, err := dst.QueryUnreceivedAcknowledgements(dstCtxLatest, srcAcks.ExtractSequenceList())
 		if err != nil {
@@ -250,13 +391,110 @@ func (st NaiveStrategy) UnrelayedAcknowledgements(src, dst *ProvableChain, sh Sy
 		return nil, err
 	}
 
+	// the fees of an acknowledged packet are escrowed on the chain that sent the packet
+	eg.Go(func() error {
+		var err error
+		srcAcks, err = filterByIncentive(dstCtxLatest, dst, srcAcks, ackFee, st.MinIncentive)
+		return err
+	})
+
+	eg.Go(func() error {
+		var err error
+		dstAcks, err = filterByIncentive(srcCtxLatest, src, dstAcks, ackFee, st.MinIncentive)
+		return err
+	})
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
 	return &RelayPackets{
-		Src: srcAcks,
-		Dst: dstAcks,
+		Src:        srcAcks,
+		Dst:        dstAcks,
+		SrcBacklog: srcBacklog,
+		DstBacklog: dstBacklog,
 	}, nil
 }
 
-// TODO add packet-timeout support
+// applyFilter returns the packets on `chain` that pass the packet filter of the strategy
+func (st NaiveStrategy) applyFilter(chain *ProvableChain, packets PacketInfoList) (PacketInfoList, error) {
+	filtered, err := st.Filter.Apply(packets)
+	if err != nil {
+		return nil, err
+	}
+	if num := len(packets) - len(filtered); num > 0 {
+		GetChainLogger(chain).Info("packets are excluded by the packet filter", "count", num)
+	}
+	return filtered, nil
+}
+
+// setupHeadersForUpdate returns `src` chain's headers needed to update the client on `dst` chain in a span
+func setupHeadersForUpdate(ctx context.Context, sh SyncHeaders, src, dst *ProvableChain) (hs []Header, err error) {
+	_, span := StartChainSpan(ctx, "SetupHeadersForUpdate", src, attribute.String("counterparty_chain_id", dst.ChainID()))
+	defer func() { EndSpan(span, err) }()
+	return sh.SetupHeadersForUpdate(ctx, src, dst)
+}
+
+// sendRelayMsgs sends the messages to their respective chains in a span
+func sendRelayMsgs(ctx context.Context, msgs *RelayMsgs, src, dst *ProvableChain) {
+	_, span := StartSpan(ctx, "RelayMsgs.Send", chainPairSpanAttributes(src, dst)...)
+	defer span.End()
+	span.SetAttributes(attribute.Int("src_msgs", len(msgs.Src)), attribute.Int("dst_msgs", len(msgs.Dst)))
+	msgs.Send(ctx, src, dst)
+	if !msgs.Success() {
+		span.SetStatus(codes.Error, "failed to send the messages")
+	}
+}
+
+// saveInFlightTxs persists the relay transactions that were not confirmed to be included in a block
+func (st NaiveStrategy) saveInFlightTxs(src, dst *ProvableChain, msgs *RelayMsgs) error {
+	if err := saveInFlightTxs(st.Checkpoints, src, msgs.SrcResults); err != nil {
+		return err
+	}
+	return saveInFlightTxs(st.Checkpoints, dst, msgs.DstResults)
+}
+
+// queryInFlightTxs returns the in-flight transactions submitted to src and dst that are still in the mempool
+func (st NaiveStrategy) queryInFlightTxs(ctx context.Context, src, dst *ProvableChain) (srcTxs, dstTxs []*InFlightTx, err error) {
+	if srcTxs, err = queryInFlightTxs(ctx, st.Checkpoints, src); err != nil {
+		return nil, nil, err
+	}
+	if dstTxs, err = queryInFlightTxs(ctx, st.Checkpoints, dst); err != nil {
+		return nil, nil, err
+	}
+	return srcTxs, dstTxs, nil
+}
+
+// checkPacketTimeouts marks the packets that can no longer be received on `counterparty` as timed out.
+// The timeouts are checked against the latest finalized header of `counterparty` because they must be proven with it.
+func checkPacketTimeouts(ctx QueryContext, counterparty *ProvableChain, packets PacketInfoList) error {
+	if len(packets) == 0 {
+		return nil
+	}
+
+	chanRes, err := counterparty.QueryChannel(ctx)
+	if err != nil {
+		return err
+	}
+	if chanRes.Channel.State == chantypes.CLOSED {
+		for _, p := range packets {
+			p.TimedOut = true
+		}
+		return nil
+	}
+
+	height := ctx.Height()
+	timestamp, err := counterparty.Timestamp(ctx.Context(), height)
+	if err != nil {
+		return err
+	}
+	for _, p := range packets {
+		p.TimedOut = (!p.TimeoutHeight.IsZero() && height.GTE(p.TimeoutHeight)) ||
+			(p.TimeoutTimestamp != 0 && uint64(timestamp.UnixNano()) >= p.TimeoutTimestamp)
+	}
+	return nil
+}
+
 func collectPackets(ctx QueryContext, chain *ProvableChain, packets PacketInfoList, signer sdk.AccAddress) ([]sdk.Msg, error) {
 	var msgs []sdk.Msg
 	for _, p := range packets {
@@ -264,7 +502,7 @@ func collectPackets(ctx QueryContext, chain *ProvableChain, packets PacketInfoLi
 		path := host.PacketCommitmentPath(p.SourcePort, p.SourceChannel, p.Sequence)
 		proof, proofHeight, err := chain.ProveState(ctx, path, commitment)
 		if err != nil {
-			log.Printf("failed to ProveState: height=%v, path=%v, commitment=%x, err=%v", ctx.Height(), path, commitment, err)
+			GetChainLogger(chain).Error("failed to prove the state", "height", ctx.Height(), "path", path, "commitment", fmt.Sprintf("%x", commitment), "error", err)
 			return nil, err
 		}
 		msg := chantypes.NewMsgRecvPacket(p.Packet, proof, proofHeight, signer.String())
@@ -273,12 +511,100 @@ func collectPackets(ctx QueryContext, chain *ProvableChain, packets PacketInfoLi
 	return msgs, nil
 }
 
-func logPacketsRelayed(src, dst Chain, num int) {
-	log.Printf("★ Relayed %d packets: [%s]port{%s}->[%s]port{%s}",
-		num, dst.ChainID(), dst.Path().PortID, src.ChainID(), src.Path().PortID)
+func logPacketsRelayed(src, dst Chain, msgs []sdk.Msg) {
+	GetChainPairLogger(dst, src).Info("packets relayed", "count", len(msgs), "sequences", msgSequences(msgs))
 }
 
-func (st NaiveStrategy) RelayAcknowledgements(src, dst *ProvableChain, sp *RelayPackets, sh SyncHeaders) error {
+// msgSequences returns the sequences of the packets relayed by the messages
+func msgSequences(msgs []sdk.Msg) []uint64 {
+	var seqs []uint64
+	for _, msg := range msgs {
+		switch msg := msg.(type) {
+		case *chantypes.MsgRecvPacket:
+			seqs = append(seqs, msg.Packet.Sequence)
+		case *chantypes.MsgAcknowledgement:
+			seqs = append(seqs, msg.Packet.Sequence)
+		case *chantypes.MsgTimeout:
+			seqs = append(seqs, msg.Packet.Sequence)
+		case *chantypes.MsgTimeoutOnClose:
+			seqs = append(seqs, msg.Packet.Sequence)
+		}
+	}
+	return seqs
+}
+
+// collectTimeouts returns MsgTimeout (or MsgTimeoutOnClose if the channel on `chain` is closed) for packets
+// that have not been received on `chain`
+func collectTimeouts(ctx QueryContext, chain *ProvableChain, packets PacketInfoList, signer sdk.AccAddress) ([]sdk.Msg, error) {
+	if len(packets) == 0 {
+		return nil, nil
+	}
+
+	chanRes, err := chain.QueryChannel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var proofClose []byte
+	if chanRes.Channel.State == chantypes.CLOSED {
+		value, err := chain.Codec().Marshal(chanRes.Channel)
+		if err != nil {
+			return nil, err
+		}
+		path := host.ChannelPath(chain.Path().PortID, chain.Path().ChannelID)
+		proofClose, _, err = chain.ProveState(ctx, path, value)
+		if err != nil {
+			GetChainLogger(chain).Error("failed to prove the state", "height", ctx.Height(), "path", path, "value", fmt.Sprintf("%x", value), "error", err)
+			return nil, err
+		}
+	}
+
+	var nextSeqRecv uint64
+	if chanRes.Channel.Ordering == chantypes.ORDERED {
+		res, err := chain.QueryNextSequenceReceive(ctx)
+		if err != nil {
+			return nil, err
+		}
+		nextSeqRecv = res.NextSequenceReceive
+	}
+
+	var msgs []sdk.Msg
+	for _, p := range packets {
+		var (
+			path  string
+			value []byte
+			seq   uint64
+		)
+		if chanRes.Channel.Ordering == chantypes.ORDERED {
+			path = host.NextSequenceRecvPath(p.DestinationPort, p.DestinationChannel)
+			value = sdk.Uint64ToBigEndian(nextSeqRecv)
+			seq = nextSeqRecv
+		} else {
+			// an absence proof of the packet receipt
+			path = host.PacketReceiptPath(p.DestinationPort, p.DestinationChannel, p.Sequence)
+			seq = p.Sequence
+		}
+		proof, proofHeight, err := chain.ProveState(ctx, path, value)
+		if err != nil {
+			GetChainLogger(chain).Error("failed to prove the state", "height", ctx.Height(), "path", path, "value", fmt.Sprintf("%x", value), "error", err)
+			return nil, err
+		}
+		var msg sdk.Msg
+		if proofClose != nil {
+			msg = chantypes.NewMsgTimeoutOnClose(p.Packet, seq, proof, proofClose, proofHeight, signer.String())
+		} else {
+			msg = chantypes.NewMsgTimeout(p.Packet, seq, proof, proofHeight, signer.String())
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func logPacketsTimedOut(src, dst Chain, msgs []sdk.Msg) {
+	GetChainPairLogger(src, dst).Info("packets timed out", "count", len(msgs), "sequences", msgSequences(msgs))
+}
+
+func (st NaiveStrategy) RelayAcknowledgements(ctx context.Context, src, dst *ProvableChain, sp *RelayPackets, sh SyncHeaders) error {
 	// set the maximum relay transaction constraints
 	msgs := &RelayMsgs{
 		Src:          []sdk.Msg{},
@@ -287,8 +613,8 @@ func (st NaiveStrategy) RelayAcknowledgements(src, dst *ProvableChain, sp *Relay
 		MaxMsgLength: st.MaxMsgLength,
 	}
 
-	srcCtx := sh.GetQueryContext(src.ChainID())
-	dstCtx := sh.GetQueryContext(dst.ChainID())
+	srcCtx := sh.GetQueryContext(ctx, src.ChainID())
+	dstCtx := sh.GetQueryContext(ctx, dst.ChainID())
 	srcAddress, err := src.GetAddress()
 	if err != nil {
 		return err
@@ -299,7 +625,7 @@ func (st NaiveStrategy) RelayAcknowledgements(src, dst *ProvableChain, sp *Relay
 	}
 
 	if len(sp.Src) > 0 {
-		hs, err := sh.SetupHeadersForUpdate(src, dst)
+		hs, err := setupHeadersForUpdate(ctx, sh, src, dst)
 		if err != nil {
 			return err
 		}
@@ -309,7 +635,7 @@ func (st NaiveStrategy) RelayAcknowledgements(src, dst *ProvableChain, sp *Relay
 	}
 
 	if len(sp.Dst) > 0 {
-		hs, err := sh.SetupHeadersForUpdate(dst, src)
+		hs, err := setupHeadersForUpdate(ctx, sh, dst, src)
 		if err != nil {
 			return err
 		}
@@ -328,8 +654,7 @@ func (st NaiveStrategy) RelayAcknowledgements(src, dst *ProvableChain, sp *Relay
 	}
 
 	if len(acksForDst) == 0 && len(acksForSrc) == 0 {
-		log.Printf("- No acknowledgements to relay between [%s]port{%s} and [%s]port{%s}",
-			src.ChainID(), src.Path().PortID, dst.ChainID(), dst.Path().PortID)
+		GetChainPairLogger(src, dst).Info("no acknowledgements to relay")
 		return nil
 	}
 
@@ -337,12 +662,17 @@ func (st NaiveStrategy) RelayAcknowledgements(src, dst *ProvableChain, sp *Relay
 	msgs.Src = append(msgs.Src, acksForSrc...)
 
 	// send messages to their respective chains
-	if msgs.Send(src, dst); msgs.Success() {
-		if num := len(acksForDst); num > 0 {
-			logPacketsRelayed(dst, src, num)
+	sendRelayMsgs(ctx, msgs, src, dst)
+	if err := st.saveInFlightTxs(src, dst, msgs); err != nil {
+		return err
+	}
+	// nothing is relayed in a dry run
+	if msgs.Success() && !src.IsDryRun() {
+		if len(acksForDst) > 0 {
+			logPacketsRelayed(dst, src, acksForDst)
 		}
-		if num := len(acksForSrc); num > 0 {
-			logPacketsRelayed(src, dst, num)
+		if len(acksForSrc) > 0 {
+			logPacketsRelayed(src, dst, acksForSrc)
 		}
 	}
 
@@ -357,7 +687,7 @@ func collectAcks(ctx QueryContext, chain *ProvableChain, packets PacketInfoList,
 		path := host.PacketAcknowledgementPath(p.DestinationPort, p.DestinationChannel, p.Sequence)
 		proof, proofHeight, err := chain.ProveState(ctx, path, commitment)
 		if err != nil {
-			log.Printf("failed to ProveState: height=%v, path=%v, commitment=%x, err=%v", ctx.Height(), path, commitment, err)
+			GetChainLogger(chain).Error("failed to prove the state", "height", ctx.Height(), "path", path, "commitment", fmt.Sprintf("%x", commitment), "error", err)
 			return nil, err
 		}
 		msg := chantypes.NewMsgAcknowledgement(p.Packet, p.Acknowledgement, proof, proofHeight, signer.String())
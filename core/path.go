@@ -102,9 +102,10 @@ func (p Paths) PathsFromChains(src, dst string) (Paths, error) {
 // Path represents a pair of chains and the identifiers needed to
 // relay over them
 type Path struct {
-	Src      *PathEnd     `yaml:"src" json:"src"`
-	Dst      *PathEnd     `yaml:"dst" json:"dst"`
-	Strategy *StrategyCfg `yaml:"strategy" json:"strategy"`
+	Src      *PathEnd      `yaml:"src" json:"src"`
+	Dst      *PathEnd      `yaml:"dst" json:"dst"`
+	Strategy *StrategyCfg  `yaml:"strategy" json:"strategy"`
+	Filter   *PacketFilter `yaml:"filter,omitempty" json:"filter,omitempty"`
 }
 
 // GenSrcClientID generates the specififed identifier
@@ -144,6 +145,11 @@ func (p *Path) Validate() (err error) {
 	if _, err = p.GetStrategy(); err != nil {
 		return err
 	}
+	if p.Filter != nil {
+		if err = p.Filter.Validate(); err != nil {
+			return fmt.Errorf("invalid packet filter: %w", err)
+		}
+	}
 	if p.Src.Order != p.Dst.Order {
 		return fmt.Errorf("both sides must have same order ('ORDERED' or 'UNORDERED'), got src(%s) and dst(%s)",
 			p.Src.Order, p.Dst.Order)
@@ -210,7 +216,7 @@ type PathWithStatus struct {
 
 // QueryPathStatus returns an instance of the path struct with some attached data about
 // the current status of the path
-func (p *Path) QueryPathStatus(src, dst *ProvableChain) *PathWithStatus {
+func (p *Path) QueryPathStatus(ctx context.Context, src, dst *ProvableChain) *PathWithStatus {
 	var (
 		err              error
 		eg               errgroup.Group
@@ -222,11 +228,11 @@ func (p *Path) QueryPathStatus(src, dst *ProvableChain) *PathWithStatus {
 		out = &PathWithStatus{Path: p, Status: PathStatus{false, false, false, false}}
 	)
 	eg.Go(func() error {
-		srch, err = src.LatestHeight()
+		srch, err = src.LatestHeight(ctx)
 		return err
 	})
 	eg.Go(func() error {
-		dsth, err = dst.LatestHeight()
+		dsth, err = dst.LatestHeight(ctx)
 		return err
 	})
 	if eg.Wait(); err != nil {
@@ -234,8 +240,6 @@ func (p *Path) QueryPathStatus(src, dst *ProvableChain) *PathWithStatus {
 	}
 	out.Status.Chains = true
 
-	ctx := context.TODO()
-
 	eg.Go(func() error {
 		srcCs, err = src.QueryClientState(NewQueryContext(ctx, srch))
 		return err
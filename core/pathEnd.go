@@ -9,14 +9,16 @@ import (
 	conntypes "github.com/cosmos/ibc-go/v7/modules/core/03-connection/types"
 	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
 	commitmenttypes "github.com/cosmos/ibc-go/v7/modules/core/23-commitment/types"
+	ibcexported "github.com/cosmos/ibc-go/v7/modules/core/exported"
 )
 
 var (
-	defaultChainPrefix = commitmenttypes.NewMerklePrefix([]byte("ibc"))
+	// DefaultCommitmentPrefix is the commitment prefix of chains whose IBC store is mounted under "ibc"
+	DefaultCommitmentPrefix = commitmenttypes.NewMerklePrefix([]byte(ibcexported.StoreKey))
 )
 
 const (
-	// TODO make it to be configurable
+	// DefaultDelayPeriod is the delay period of connections whose path end doesn't configure one
 	DefaultDelayPeriod uint64 = 0
 )
 
@@ -30,6 +32,13 @@ type PathEnd struct {
 	PortID       string `yaml:"port-id,omitempty" json:"port-id,omitempty"`
 	Order        string `yaml:"order,omitempty" json:"order,omitempty"`
 	Version      string `yaml:"version,omitempty" json:"version,omitempty"`
+
+	// DelayPeriod is the delay period of the connection in nanoseconds
+	DelayPeriod uint64 `yaml:"delay-period,omitempty" json:"delay-period,omitempty"`
+	// ConnectionVersion and ConnectionFeatures are the connection version proposed in `connOpenInit`.
+	// All the compatible versions are proposed if ConnectionVersion is empty.
+	ConnectionVersion  string   `yaml:"connection-version,omitempty" json:"connection-version,omitempty"`
+	ConnectionFeatures []string `yaml:"connection-features,omitempty" json:"connection-features,omitempty"`
 }
 
 // OrderFromString parses a string into a channel order byte
@@ -48,6 +57,23 @@ func (pe *PathEnd) GetOrder() chantypes.Order {
 	return OrderFromString(strings.ToUpper(pe.Order))
 }
 
+// GetConnectionVersion returns the connection version proposed in `connOpenInit`, or nil if no version is configured.
+// If no features are configured, those of the compatible version with the same identifier are used.
+func (pe *PathEnd) GetConnectionVersion() *conntypes.Version {
+	if pe.ConnectionVersion == "" {
+		return nil
+	}
+	features := pe.ConnectionFeatures
+	if len(features) == 0 {
+		for _, v := range conntypes.GetCompatibleVersions() {
+			if v.GetIdentifier() == pe.ConnectionVersion {
+				features = v.GetFeatures()
+			}
+		}
+	}
+	return conntypes.NewVersion(pe.ConnectionVersion, features)
+}
+
 // UpdateClient creates an sdk.Msg to update the client on src with data pulled from dst
 func (pe *PathEnd) UpdateClient(dstHeader Header, signer sdk.AccAddress) sdk.Msg {
 	if err := dstHeader.ValidateBasic(); err != nil {
@@ -73,22 +99,24 @@ func (pe *PathEnd) UpdateClients(dstHeaders []Header, signer sdk.AccAddress) []s
 }
 
 // ConnInit creates a MsgConnectionOpenInit
-func (pe *PathEnd) ConnInit(dst *PathEnd, signer sdk.AccAddress) sdk.Msg {
-	var version *conntypes.Version
+// `dstPrefix` is the commitment prefix of the counterparty chain.
+func (pe *PathEnd) ConnInit(dst *PathEnd, dstPrefix commitmenttypes.MerklePrefix, signer sdk.AccAddress) sdk.Msg {
 	return conntypes.NewMsgConnectionOpenInit(
 		pe.ClientID,
 		dst.ClientID,
-		defaultChainPrefix,
-		version,
-		DefaultDelayPeriod,
+		dstPrefix,
+		pe.GetConnectionVersion(),
+		pe.DelayPeriod,
 		signer.String(),
 	)
 }
 
 // ConnTry creates a MsgConnectionOpenTry
+// The versions and the delay period are taken from the connection on dst so that they match the ones proved.
 // NOTE: ADD NOTE ABOUT PROOF HEIGHT CHANGE HERE
 func (pe *PathEnd) ConnTry(
 	dst *PathEnd,
+	dstPrefix commitmenttypes.MerklePrefix,
 	dstClientState *clienttypes.QueryClientStateResponse,
 	dstConnState *conntypes.QueryConnectionResponse,
 	dstConsState *clienttypes.QueryConsensusStateResponse,
@@ -103,9 +131,9 @@ func (pe *PathEnd) ConnTry(
 		dst.ConnectionID,
 		dst.ClientID,
 		cs,
-		defaultChainPrefix,
-		conntypes.ExportedVersionsToProto(conntypes.GetCompatibleVersions()),
-		DefaultDelayPeriod,
+		dstPrefix,
+		dstConnState.Connection.Versions,
+		dstConnState.Connection.DelayPeriod,
 		dstConnState.Proof,
 		dstClientState.Proof,
 		dstConsState.Proof,
@@ -141,7 +169,7 @@ func (pe *PathEnd) ConnAck(
 		dstConsState.Proof,
 		dstConsState.ProofHeight,
 		cs.GetLatestHeight().(clienttypes.Height),
-		conntypes.ExportedVersionsToProto(conntypes.GetCompatibleVersions())[0],
+		dstConnState.Connection.Versions[0], // the version selected in `connOpenTry`
 		signer.String(),
 	)
 }
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"log/slog"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/codec"
@@ -12,7 +13,7 @@ import (
 // Prover represents a prover that supports generating a commitment proof
 type Prover interface {
 	// Init initializes the chain
-	Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, debug bool) error
+	Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, logger *slog.Logger, debug bool) error
 
 	// SetRelayInfo sets source's path and counterparty's info to the chain
 	SetRelayInfo(path *PathEnd, counterparty *ProvableChain, counterpartyPath *PathEnd) error
@@ -36,12 +37,12 @@ type LightClient interface {
 
 	// GetLatestFinalizedHeader returns the latest finalized header on this chain
 	// The returned header is expected to be the latest one of headers that can be verified by the light client
-	GetLatestFinalizedHeader() (latestFinalizedHeader Header, err error)
+	GetLatestFinalizedHeader(ctx context.Context) (latestFinalizedHeader Header, err error)
 
 	// SetupHeadersForUpdate returns the finalized header and any intermediate headers needed to apply it to the client on the counterpaty chain
 	// The order of the returned header slice should be as: [<intermediate headers>..., <update header>]
 	// if the header slice's length == 0 and err == nil, the relayer should skips the update-client
-	SetupHeadersForUpdate(dstChain ChainInfoICS02Querier, latestFinalizedHeader Header) ([]Header, error)
+	SetupHeadersForUpdate(ctx context.Context, dstChain ChainInfoICS02Querier, latestFinalizedHeader Header) ([]Header, error)
 }
 
 // ChainInfoICS02Querier is ChainInfo + ICS02Querier
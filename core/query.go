@@ -1,6 +1,8 @@
 package core
 
 import (
+	"context"
+
 	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
 	conntypes "github.com/cosmos/ibc-go/v7/modules/core/03-connection/types"
 	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
@@ -170,3 +172,15 @@ func QueryChannelPair(srcCtx, dstCtx QueryContext, src, dst interface {
 	err = eg.Wait()
 	return
 }
+
+// QueryUnreceivedOnCounterparty returns the sequences that are unreceived at the latest finalized height of the counterparty
+func QueryUnreceivedOnCounterparty(ctx context.Context, counterparty LightClientICS04Querier, seqs []uint64, queryUnreceived func(QueryContext, []uint64) ([]uint64, error)) ([]uint64, error) {
+	if len(seqs) == 0 {
+		return nil, nil
+	}
+	h, err := counterparty.GetLatestFinalizedHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return queryUnreceived(NewQueryContext(ctx, h.GetHeight()), seqs)
+}
@@ -1,6 +1,9 @@
 package core
 
 import (
+	"context"
+	"sync"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/gogoproto/proto"
 )
@@ -16,6 +19,10 @@ type RelayMsgs struct {
 
 	Last      bool `json:"last"`
 	Succeeded bool `json:"success"`
+
+	// results of the batches sent by the last Send, in the order of submission
+	SrcResults []*BatchResult `json:"-"`
+	DstResults []*BatchResult `json:"-"`
 }
 
 // NewRelayMsgs returns an initialized version of relay messages
@@ -45,19 +52,39 @@ func (r *RelayMsgs) IsMaxTx(msgLen, txSize uint64) bool {
 		(r.MaxTxSize != 0 && txSize > r.MaxTxSize)
 }
 
-// Send sends the messages with appropriate output
-// TODO: Parallelize? Maybe?
-func (r *RelayMsgs) Send(src, dst Chain) {
+// Send sends the src and dst messages concurrently in batches bounded by MaxTxSize and MaxMsgLength.
+// The batches of each chain are sent in order, and the remaining batches of a chain are skipped after a failed one
+// because they may depend on it. A failure on one chain doesn't stop the submissions to the other.
+// Once `ctx` is done, the batches in flight are sent to the end and the rest are skipped.
+func (r *RelayMsgs) Send(ctx context.Context, src, dst Chain) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		r.SrcResults = r.sendBatches(ctx, src, r.Src)
+	}()
+	go func() {
+		defer wg.Done()
+		r.DstResults = r.sendBatches(ctx, dst, r.Dst)
+	}()
+	wg.Wait()
+
+	r.Succeeded = batchesSucceeded(r.SrcResults) && batchesSucceeded(r.DstResults)
+
+	metrics.observeBatches(src, dst, r.SrcResults)
+	metrics.observeBatches(dst, src, r.DstResults)
+}
+
+// sendBatches submits batches of the msgs to the chain and returns the result of each batch
+func (r *RelayMsgs) sendBatches(ctx context.Context, chain Chain, msgs []sdk.Msg) []*BatchResult {
 	//nolint:prealloc // can not be pre allocated
 	var (
 		msgLen, txSize uint64
-		msgs           []sdk.Msg
+		batches        [][]sdk.Msg
+		batch          []sdk.Msg
 	)
 
-	r.Succeeded = true
-
-	// submit batches of relay transactions
-	for _, msg := range r.Src {
+	for _, msg := range msgs {
 		bz, err := proto.Marshal(msg)
 		if err != nil {
 			panic(err)
@@ -66,48 +93,61 @@ func (r *RelayMsgs) Send(src, dst Chain) {
 		msgLen++
 		txSize += uint64(len(bz))
 
-		if r.IsMaxTx(msgLen, txSize) {
-			// Submit the transactions to src chain and update its status
-			r.Succeeded = r.Succeeded && src.Send(msgs)
+		if r.IsMaxTx(msgLen, txSize) && len(batch) > 0 {
+			batches = append(batches, batch)
 
 			// clear the current batch and reset variables
 			msgLen, txSize = 1, uint64(len(bz))
-			msgs = []sdk.Msg{}
+			batch = []sdk.Msg{}
 		}
-		msgs = append(msgs, msg)
+		batch = append(batch, msg)
 	}
-
-	// submit leftover msgs
-	if len(msgs) > 0 && !src.Send(msgs) {
-		r.Succeeded = false
+	// leftover msgs
+	if len(batch) > 0 {
+		batches = append(batches, batch)
 	}
 
-	// reset variables
-	msgLen, txSize = 0, 0
-	msgs = []sdk.Msg{}
-
-	for _, msg := range r.Dst {
-		bz, err := proto.Marshal(msg)
+	var results []*BatchResult
+	for _, batch := range batches {
+		if err := ctx.Err(); err != nil {
+			results = append(results, &BatchResult{Msgs: batch, Err: err})
+			break
+		}
+		// a broadcast is not interrupted halfway so that the sequence of the signer stays consistent
+		res, err := chain.Send(context.WithoutCancel(ctx), batch)
+		results = append(results, &BatchResult{Msgs: batch, Result: res, Err: err})
 		if err != nil {
-			panic(err)
+			break
 		}
+	}
+	return results
+}
 
-		msgLen++
-		txSize += uint64(len(bz))
-
-		if r.IsMaxTx(msgLen, txSize) {
-			// Submit the transaction to dst chain and update its status
-			r.Succeeded = r.Succeeded && dst.Send(msgs)
+// BatchResult is the result of submitting a batch of relay msgs in a transaction
+type BatchResult struct {
+	Msgs   []sdk.Msg
+	Result *TxResult // nil if the transaction isn't included in a block
+	Err    error
+}
 
-			// clear the current batch and reset variables
-			msgLen, txSize = 1, uint64(len(bz))
-			msgs = []sdk.Msg{}
+// batchErrors returns the errors of the failed batches
+func batchErrors(results ...[]*BatchResult) []error {
+	var errs []error
+	for _, rs := range results {
+		for _, res := range rs {
+			if res.Err != nil {
+				errs = append(errs, res.Err)
+			}
 		}
-		msgs = append(msgs, msg)
 	}
+	return errs
+}
 
-	// submit leftover msgs
-	if len(msgs) > 0 && !dst.Send(msgs) {
-		r.Succeeded = false
+func batchesSucceeded(results []*BatchResult) bool {
+	for _, res := range results {
+		if res.Err != nil {
+			return false
+		}
 	}
+	return true
 }
@@ -0,0 +1,248 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	retry "github.com/avast/retry-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// RetryAllErrors makes all errors retryable
+	RetryAllErrors = "all"
+	// RetryNetworkErrors makes connection failures retryable
+	RetryNetworkErrors = "network"
+	// RetryTimeoutErrors makes timeouts retryable
+	RetryTimeoutErrors = "timeout"
+	// RetryRateLimitErrors makes the rejections by rate limits retryable
+	RetryRateLimitErrors = "rate-limit"
+)
+
+// RetryPolicy defines how failed operations are retried.
+// The delay before the n-th retry is `initial-delay * 2^n` plus a random jitter up to `max-jitter`, capped at `max-delay`.
+// The zero value of each field means the default.
+type RetryPolicy struct {
+	Attempts     uint   `yaml:"attempts,omitempty" json:"attempts,omitempty"`
+	InitialDelay string `yaml:"initial-delay,omitempty" json:"initial-delay,omitempty"`
+	MaxDelay     string `yaml:"max-delay,omitempty" json:"max-delay,omitempty"`
+	MaxJitter    string `yaml:"max-jitter,omitempty" json:"max-jitter,omitempty"`
+	// RetryableErrors are the classes of errors that are retried: "all", "network", "timeout" or "rate-limit"
+	RetryableErrors []string `yaml:"retryable-errors,omitempty" json:"retryable-errors,omitempty"`
+}
+
+// DefaultRetryPolicy returns the retry policy used for the fields that aren't configured
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		Attempts:        5,
+		InitialDelay:    "400ms",
+		MaxDelay:        "1m",
+		MaxJitter:       "100ms",
+		RetryableErrors: []string{RetryAllErrors},
+	}
+}
+
+// Validate checks that the fields of the policy are valid
+func (p *RetryPolicy) Validate() error {
+	for name, d := range map[string]string{"initial-delay": p.InitialDelay, "max-delay": p.MaxDelay, "max-jitter": p.MaxJitter} {
+		if d == "" {
+			continue
+		}
+		if v, err := time.ParseDuration(d); err != nil {
+			return fmt.Errorf("invalid %s '%s': %w", name, d, err)
+		} else if v < 0 {
+			return fmt.Errorf("%s must not be negative: %s", name, d)
+		}
+	}
+	for _, class := range p.RetryableErrors {
+		switch class {
+		case RetryAllErrors, RetryNetworkErrors, RetryTimeoutErrors, RetryRateLimitErrors:
+		default:
+			return fmt.Errorf("unknown retryable error class '%s'", class)
+		}
+	}
+	return nil
+}
+
+// Merge returns a copy of the policy whose fields are overridden by the non-zero fields of `override`
+func (p *RetryPolicy) Merge(override *RetryPolicy) *RetryPolicy {
+	ret := *p
+	if override == nil {
+		return &ret
+	}
+	if override.Attempts != 0 {
+		ret.Attempts = override.Attempts
+	}
+	if override.InitialDelay != "" {
+		ret.InitialDelay = override.InitialDelay
+	}
+	if override.MaxDelay != "" {
+		ret.MaxDelay = override.MaxDelay
+	}
+	if override.MaxJitter != "" {
+		ret.MaxJitter = override.MaxJitter
+	}
+	if len(override.RetryableErrors) > 0 {
+		ret.RetryableErrors = override.RetryableErrors
+	}
+	return &ret
+}
+
+// resolve returns the policy with the default values set to the fields that aren't configured
+func (p *RetryPolicy) resolve() *RetryPolicy {
+	if p == nil {
+		return DefaultRetryPolicy()
+	}
+	return DefaultRetryPolicy().Merge(p)
+}
+
+func (p *RetryPolicy) durations() (initial, max, jitter time.Duration) {
+	r := p.resolve()
+	// the durations are checked by Validate
+	initial, _ = time.ParseDuration(r.InitialDelay)
+	max, _ = time.ParseDuration(r.MaxDelay)
+	jitter, _ = time.ParseDuration(r.MaxJitter)
+	return initial, max, jitter
+}
+
+// Options returns the options of retry.Do that implement the policy
+func (p *RetryPolicy) Options() []retry.Option {
+	initial, max, jitter := p.durations()
+	delayType := retry.BackOffDelay
+	if jitter > 0 {
+		delayType = retry.CombineDelay(retry.BackOffDelay, retry.RandomDelay)
+	}
+	return []retry.Option{
+		retry.Attempts(p.GetAttempts()),
+		retry.Delay(initial),
+		retry.MaxDelay(max),
+		retry.MaxJitter(jitter),
+		retry.DelayType(delayType),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(p.IsRetryable),
+	}
+}
+
+// Do calls `f` until it succeeds, the policy gives up or `ctx` is done, and returns the last error.
+// `onRetry` is called after each failed attempt that will be retried if it is not nil.
+func (p *RetryPolicy) Do(ctx context.Context, f func() error, onRetry func(n uint, err error)) error {
+	opts := append(p.Options(), retry.Context(ctx))
+	if onRetry != nil {
+		opts = append(opts, retry.OnRetry(onRetry))
+	}
+	return retry.Do(f, opts...)
+}
+
+// GetAttempts returns the number of attempts including the first one
+func (p *RetryPolicy) GetAttempts() uint {
+	return p.resolve().Attempts
+}
+
+// Backoff returns the delay before the retry after `n` consecutive failures (n >= 1)
+func (p *RetryPolicy) Backoff(n uint) time.Duration {
+	initial, max, jitter := p.durations()
+	d := initial
+	for i := uint(1); i < n && (max == 0 || d < max); i++ {
+		d *= 2
+	}
+	if jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(jitter)))
+	}
+	if max > 0 && d > max {
+		d = max
+	}
+	return d
+}
+
+// sleepContext waits for `d` and returns the error of `ctx` if it's done before that
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// IsRetryable returns true if the error belongs to any of the retryable error classes of the policy
+func (p *RetryPolicy) IsRetryable(err error) bool {
+	if !retry.IsRecoverable(err) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	for _, class := range p.resolve().RetryableErrors {
+		switch class {
+		case RetryAllErrors:
+			return true
+		case RetryNetworkErrors:
+			if isNetworkError(err) {
+				return true
+			}
+		case RetryTimeoutErrors:
+			if isTimeoutError(err) {
+				return true
+			}
+		case RetryRateLimitErrors:
+			if isRateLimitError(err) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RetryPolicySetter is an optional interface of Chain and Prover to retry their operations with the policy of the chain
+type RetryPolicySetter interface {
+	SetRetryPolicy(policy *RetryPolicy)
+}
+
+func isNetworkError(err error) bool {
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	if s, ok := status.FromError(err); ok && s.Code() == codes.Unavailable {
+		return true
+	}
+	// the RPC clients don't always wrap the underlying errors
+	return containsAny(err.Error(), "connection refused", "connection reset", "no such host", "broken pipe", "EOF")
+}
+
+func isTimeoutError(err error) bool {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return true
+	}
+	if s, ok := status.FromError(err); ok && s.Code() == codes.DeadlineExceeded {
+		return true
+	}
+	return containsAny(strings.ToLower(err.Error()), "timeout", "timed out", "deadline exceeded")
+}
+
+func isRateLimitError(err error) bool {
+	if s, ok := status.FromError(err); ok && s.Code() == codes.ResourceExhausted {
+		return true
+	}
+	return containsAny(strings.ToLower(err.Error()), "429", "too many requests", "rate limit")
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
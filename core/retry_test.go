@@ -0,0 +1,74 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+
+	retry "github.com/avast/retry-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryPolicyIsRetryable(t *testing.T) {
+	var (
+		connRefused = &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+		deadline    = fmt.Errorf("query failed: %w", context.DeadlineExceeded)
+		rateLimited = status.Error(codes.ResourceExhausted, "quota exceeded")
+		other       = errors.New("insufficient funds")
+	)
+	cases := []struct {
+		name    string
+		classes []string
+		err     error
+		want    bool
+	}{
+		{"default retries all", nil, other, true},
+		{"all", []string{RetryAllErrors}, other, true},
+		{"canceled", []string{RetryAllErrors}, fmt.Errorf("wrapped: %w", context.Canceled), false},
+		{"unrecoverable", []string{RetryAllErrors}, retry.Unrecoverable(connRefused), false},
+		{"network: op error", []string{RetryNetworkErrors}, connRefused, true},
+		{"network: EOF", []string{RetryNetworkErrors}, fmt.Errorf("read: %w", io.EOF), true},
+		{"network: unavailable", []string{RetryNetworkErrors}, status.Error(codes.Unavailable, "down"), true},
+		{"network: message", []string{RetryNetworkErrors}, errors.New("post failed: connection reset by peer"), true},
+		{"network: other", []string{RetryNetworkErrors}, other, false},
+		{"network: timeout", []string{RetryNetworkErrors}, deadline, false},
+		{"timeout: deadline", []string{RetryTimeoutErrors}, deadline, true},
+		{"timeout: grpc", []string{RetryTimeoutErrors}, status.Error(codes.DeadlineExceeded, "slow"), true},
+		{"timeout: message", []string{RetryTimeoutErrors}, errors.New("Request Timed Out"), true},
+		{"timeout: other", []string{RetryTimeoutErrors}, other, false},
+		{"rate limit: grpc", []string{RetryRateLimitErrors}, rateLimited, true},
+		{"rate limit: http", []string{RetryRateLimitErrors}, errors.New("server returned 429 Too Many Requests"), true},
+		{"rate limit: other", []string{RetryRateLimitErrors}, other, false},
+		{"any class", []string{RetryTimeoutErrors, RetryRateLimitErrors}, rateLimited, true},
+	}
+	for _, c := range cases {
+		p := &RetryPolicy{RetryableErrors: c.classes}
+		if got := p.IsRetryable(c.err); got != c.want {
+			t.Errorf("%s: IsRetryable(%v) = %v, want %v", c.name, c.err, got, c.want)
+		}
+	}
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		policy  RetryPolicy
+		wantErr bool
+	}{
+		{"empty", RetryPolicy{}, false},
+		{"default", *DefaultRetryPolicy(), false},
+		{"invalid duration", RetryPolicy{MaxDelay: "1 minute"}, true},
+		{"negative duration", RetryPolicy{InitialDelay: "-1s"}, true},
+		{"unknown class", RetryPolicy{RetryableErrors: []string{"network", "server"}}, true},
+	}
+	for _, c := range cases {
+		if err := c.policy.Validate(); (err != nil) != c.wantErr {
+			t.Errorf("%s: Validate returned %v, want error: %v", c.name, err, c.wantErr)
+		}
+	}
+}
@@ -2,19 +2,20 @@ package core
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"time"
 
 	retry "github.com/avast/retry-go"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // StartService starts a relay service
-func StartService(ctx context.Context, st StrategyI, src, dst *ProvableChain, relayInterval time.Duration) error {
-	sh, err := NewSyncHeaders(src, dst)
+func StartService(ctx context.Context, st StrategyI, src, dst *ProvableChain, relayInterval time.Duration, opts ...RelayServiceOption) error {
+	sh, err := NewSyncHeaders(ctx, src, dst)
 	if err != nil {
 		return err
 	}
-	srv := NewRelayService(st, src, dst, sh, relayInterval)
+	srv := NewRelayService(st, src, dst, sh, relayInterval, opts...)
 	return srv.Start(ctx)
 }
 
@@ -24,64 +25,245 @@ type RelayService struct {
 	st       StrategyI
 	sh       SyncHeaders
 	interval time.Duration
+
+	eventDriven            bool
+	clientRefreshThreshold float64
+	misbehaviourDetection  bool
+	retryPolicy            *RetryPolicy
+
+	// next heights to scan client updates for misbehaviours, keyed by chain ID
+	misbehaviourScanHeights map[string]uint64
+
+	// binding is set if the chains are shared with other paths
+	binding *pathBinding
+
+	// control is set if the service is controlled via the admin API
+	control *PathControl
+}
+
+// RelayServiceOption configures an optional behaviour of RelayService
+type RelayServiceOption func(*RelayService)
+
+// WithEventDriven makes the service perform relays when the chains notify relevant events.
+// The relay interval is used as a fallback heartbeat.
+func WithEventDriven() RelayServiceOption {
+	return func(srv *RelayService) {
+		srv.eventDriven = true
+	}
+}
+
+// WithClientRefresh makes the service update the clients when the given fraction of their trusting periods
+// has elapsed since their latest consensus states. A threshold of zero disables the refresh.
+func WithClientRefresh(threshold float64) RelayServiceOption {
+	return func(srv *RelayService) {
+		srv.clientRefreshThreshold = threshold
+	}
+}
+
+// WithMisbehaviourDetection makes the service check the headers submitted to the clients on both chains
+// and submit misbehaviours to freeze the clients if the headers conflict with the chains
+func WithMisbehaviourDetection() RelayServiceOption {
+	return func(srv *RelayService) {
+		srv.misbehaviourDetection = true
+	}
+}
+
+// WithCheckpointStore makes the strategy of the service persist its relay progress in the store
+// so that only the packets since the last relay are queried. It has no effect on strategies without checkpoint support.
+func WithCheckpointStore(store *CheckpointStore) RelayServiceOption {
+	return func(srv *RelayService) {
+		if st, ok := srv.st.(CheckpointStrategy); ok {
+			st.SetCheckpointStore(store)
+		}
+	}
+}
+
+// WithRetryPolicy makes the service retry failed relays with the policy instead of the default one
+func WithRetryPolicy(policy *RetryPolicy) RelayServiceOption {
+	return func(srv *RelayService) {
+		srv.retryPolicy = policy
+	}
 }
 
 // NewRelayService returns a new service
-func NewRelayService(st StrategyI, src, dst *ProvableChain, sh SyncHeaders, interval time.Duration) *RelayService {
-	return &RelayService{
+func NewRelayService(st StrategyI, src, dst *ProvableChain, sh SyncHeaders, interval time.Duration, opts ...RelayServiceOption) *RelayService {
+	srv := &RelayService{
 		src:      src,
 		dst:      dst,
 		st:       st,
 		sh:       sh,
 		interval: interval,
+
+		misbehaviourScanHeights: make(map[string]uint64),
+	}
+	for _, opt := range opts {
+		opt(srv)
 	}
+	return srv
 }
 
 // Start starts a relay service
 func (srv *RelayService) Start(ctx context.Context) error {
+	var wake <-chan struct{}
+	if err := srv.withBinding(func() error {
+		var err error
+		wake, err = srv.subscribeRelayEvents(ctx)
+		return err
+	}); err != nil {
+		return err
+	}
+	srv.control.attach(srv)
+	defer srv.control.detach()
 	for {
-		if err := retry.Do(func() error {
+		policy := srv.retryPolicy.resolve()
+		if srv.control.isPaused() {
+			// skip the relay until the service is resumed
+		} else if err := policy.Do(ctx, func() error {
 			select {
 			case <-ctx.Done():
 				return retry.Unrecoverable(ctx.Err())
 			default:
-				return srv.Serve(ctx)
+				return srv.withBinding(func() error {
+					return srv.Serve(ctx)
+				})
 			}
-		}, rtyAtt, rtyDel, rtyErr, retry.OnRetry(func(n uint, err error) {
-			log.Printf("- [%s][%s]try(%d/%d) relay-service: %s", srv.src.ChainID(), srv.dst.ChainID(), n+1, rtyAttNum, err)
-		})); err != nil {
+		}, func(n uint, err error) {
+			GetChainPairLogger(srv.src, srv.dst).Info("retrying the relay service", "attempt", n+1, "max_attempts", policy.GetAttempts(), "error", err)
+		}); err != nil {
 			return err
 		}
-		time.Sleep(srv.interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		case <-srv.control.triggered():
+		case <-time.After(srv.interval):
+		}
 	}
 }
 
-// Serve performs packet-relay
-func (srv *RelayService) Serve(ctx context.Context) error {
+// withBinding calls `f` while the path info of the service is bound to the shared chains
+func (srv *RelayService) withBinding(f func() error) error {
+	if srv.binding == nil {
+		return f()
+	}
+	if err := srv.binding.acquire(); err != nil {
+		return err
+	}
+	defer srv.binding.release()
+	return f()
+}
+
+// subscribeRelayEvents returns a channel that receives a value when either src or dst notifies relevant events.
+// It returns a nil channel if the service is not event-driven.
+func (srv *RelayService) subscribeRelayEvents(ctx context.Context) (<-chan struct{}, error) {
+	if !srv.eventDriven {
+		return nil, nil
+	}
+	wake := make(chan struct{}, 1)
+	for _, chain := range []*ProvableChain{srv.src, srv.dst} {
+		sub, ok := chain.Chain.(RelayEventSubscriber)
+		if !ok {
+			return nil, fmt.Errorf("chain %s does not support event subscriptions", chain.ChainID())
+		}
+		events, err := sub.SubscribeRelayEvents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		chain := chain
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case _, ok := <-events:
+					if !ok {
+						GetChainLogger(chain).Error("relay event subscription was closed, falling back to the relay interval", "interval", srv.interval)
+						return
+					}
+					select {
+					case wake <- struct{}{}:
+					default:
+					}
+				}
+			}
+		}()
+	}
+	return wake, nil
+}
+
+// Serve performs packet-relay. Each call is traced as a root span with child spans of its phases.
+func (srv *RelayService) Serve(ctx context.Context) (err error) {
+	startedAt := time.Now()
+	ctx, span := tracer.Start(ctx, "RelayService.Serve",
+		trace.WithNewRoot(),
+		trace.WithAttributes(chainPairSpanAttributes(srv.src, srv.dst)...),
+	)
+	defer func() {
+		srv.control.observeServe(startedAt, err)
+		EndSpan(span, err)
+	}()
+
 	// First, update the latest headers for src and dst
-	if err := srv.sh.Updates(srv.src, srv.dst); err != nil {
+	if err := srv.phase(ctx, "SyncHeaders.Updates", func(ctx context.Context) error {
+		return srv.sh.Updates(ctx, srv.src, srv.dst)
+	}); err != nil {
+		return err
+	}
+	metrics.observeFinalizedHeights(srv.sh, srv.src, srv.dst)
+
+	// refresh the clients before their trusting periods expire
+	if err := srv.phase(ctx, "RefreshClients", srv.refreshClients); err != nil {
+		return err
+	}
+
+	if err := srv.phase(ctx, "CheckMisbehaviours", srv.checkMisbehaviours); err != nil {
+		return err
+	}
+
+	// finish the closing handshake if the channel has been closed on either end
+	if err := srv.phase(ctx, "FinishChannelClose", srv.finishChannelClose); err != nil {
 		return err
 	}
 
 	// relay packets if unrelayed seqs exist
 
-	pseqs, err := srv.st.UnrelayedPackets(srv.src, srv.dst, srv.sh)
-	if err != nil {
+	var pseqs *RelayPackets
+	if err := srv.phase(ctx, "UnrelayedPackets", func(ctx context.Context) (err error) {
+		pseqs, err = srv.st.UnrelayedPackets(ctx, srv.src, srv.dst, srv.sh)
+		return err
+	}); err != nil {
 		return err
 	}
-	if err := srv.st.RelayPackets(srv.src, srv.dst, pseqs, srv.sh); err != nil {
+	if err := srv.phase(ctx, "RelayPackets", func(ctx context.Context) error {
+		return srv.st.RelayPackets(ctx, srv.src, srv.dst, pseqs, srv.sh)
+	}); err != nil {
 		return err
 	}
 
 	// relay acks if unrelayed seqs exist
 
-	aseqs, err := srv.st.UnrelayedAcknowledgements(srv.src, srv.dst, srv.sh)
-	if err != nil {
+	var aseqs *RelayPackets
+	if err := srv.phase(ctx, "UnrelayedAcknowledgements", func(ctx context.Context) (err error) {
+		aseqs, err = srv.st.UnrelayedAcknowledgements(ctx, srv.src, srv.dst, srv.sh)
+		return err
+	}); err != nil {
 		return err
 	}
-	if err := srv.st.RelayAcknowledgements(srv.src, srv.dst, aseqs, srv.sh); err != nil {
+	if err := srv.phase(ctx, "RelayAcknowledgements", func(ctx context.Context) error {
+		return srv.st.RelayAcknowledgements(ctx, srv.src, srv.dst, aseqs, srv.sh)
+	}); err != nil {
 		return err
 	}
 
+	metrics.observeRelay(srv.src, srv.dst, pseqs, aseqs, time.Since(startedAt))
+	srv.control.observeBacklog(pseqs, aseqs)
 	return nil
 }
+
+// phase runs `f` in a child span of the span in `ctx`
+func (srv *RelayService) phase(ctx context.Context, name string, f func(context.Context) error) (err error) {
+	ctx, span := StartSpan(ctx, name)
+	defer func() { EndSpan(span, err) }()
+	return f(ctx)
+}
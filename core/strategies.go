@@ -1,40 +1,152 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 )
 
 // StrategyI defines
 type StrategyI interface {
 	GetType() string
 	SetupRelay(ctx context.Context, src, dst *ProvableChain) error
-	UnrelayedPackets(src, dst *ProvableChain, sh SyncHeaders) (*RelayPackets, error)
-	RelayPackets(src, dst *ProvableChain, rp *RelayPackets, sh SyncHeaders) error
-	UnrelayedAcknowledgements(src, dst *ProvableChain, sh SyncHeaders) (*RelayPackets, error)
-	RelayAcknowledgements(src, dst *ProvableChain, ra *RelayPackets, sh SyncHeaders) error
+	UnrelayedPackets(ctx context.Context, src, dst *ProvableChain, sh SyncHeaders) (*RelayPackets, error)
+	RelayPackets(ctx context.Context, src, dst *ProvableChain, rp *RelayPackets, sh SyncHeaders) error
+	UnrelayedAcknowledgements(ctx context.Context, src, dst *ProvableChain, sh SyncHeaders) (*RelayPackets, error)
+	RelayAcknowledgements(ctx context.Context, src, dst *ProvableChain, ra *RelayPackets, sh SyncHeaders) error
 }
 
 // StrategyCfg defines which relaying strategy to take for a given path
 type StrategyCfg struct {
 	Type string `json:"type" yaml:"type"`
+	// Options is the strategy-specific options as a JSON object. Each strategy defines and validates its format.
+	Options json.RawMessage `json:"options,omitempty" yaml:"options,omitempty"`
 }
 
+// MarshalYAML implements yaml.Marshaler so that the options are encoded as a YAML mapping instead of the bytes of the JSON
+func (cfg StrategyCfg) MarshalYAML() (interface{}, error) {
+	var options interface{}
+	if len(cfg.Options) > 0 {
+		if err := json.Unmarshal(cfg.Options, &options); err != nil {
+			return nil, fmt.Errorf("invalid options of strategy '%s': %w", cfg.Type, err)
+		}
+	}
+	return struct {
+		Type    string      `yaml:"type"`
+		Options interface{} `yaml:"options,omitempty"`
+	}{cfg.Type, options}, nil
+}
+
+// UnmarshalOptions decodes the options into `v`, rejecting unknown fields. It does nothing if the options are empty.
+func (cfg StrategyCfg) UnmarshalOptions(v interface{}) error {
+	if len(cfg.Options) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(cfg.Options))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid options of strategy '%s': %w", cfg.Type, err)
+	}
+	return nil
+}
+
+// WithOptionOverrides returns a copy of the config whose options are overridden by the fields of `overrides`
+func (cfg StrategyCfg) WithOptionOverrides(overrides json.RawMessage) (StrategyCfg, error) {
+	if len(overrides) == 0 {
+		return cfg, nil
+	}
+	options := make(map[string]json.RawMessage)
+	if len(cfg.Options) > 0 {
+		if err := json.Unmarshal(cfg.Options, &options); err != nil {
+			return cfg, fmt.Errorf("options of strategy '%s' must be a JSON object: %w", cfg.Type, err)
+		}
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(overrides, &fields); err != nil {
+		return cfg, fmt.Errorf("option overrides must be a JSON object: %w", err)
+	}
+	for k, v := range fields {
+		options[k] = v
+	}
+	bz, err := json.Marshal(options)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Options = bz
+	return cfg, nil
+}
+
+// StrategyBuilder builds a strategy for the given path from its strategy config
+type StrategyBuilder func(path *Path) (StrategyI, error)
+
+// StrategyRegistry is a registry of strategies that `StrategyCfg.Type` resolves to
+type StrategyRegistry interface {
+	// RegisterStrategy registers a builder of the strategy type
+	RegisterStrategy(strategyType string, builder StrategyBuilder) error
+}
+
+type strategyRegistry map[string]StrategyBuilder
+
+var _ StrategyRegistry = strategyRegistry(nil)
+
+// RegisterStrategy implements StrategyRegistry
+func (r strategyRegistry) RegisterStrategy(strategyType string, builder StrategyBuilder) error {
+	if strategyType == "" {
+		return fmt.Errorf("strategy type must not be empty")
+	}
+	if _, found := r[strategyType]; found {
+		return fmt.Errorf("strategy type '%s' is already registered", strategyType)
+	}
+	r[strategyType] = builder
+	return nil
+}
+
+// strategies holds the registered strategies
+// NOTE: strategies must be registered before the relay starts because the registry is not goroutine-safe
+var strategies = strategyRegistry{
+	(&NaiveStrategy{}).GetType(): newNaiveStrategy,
+}
+
+// DefaultStrategyRegistry returns the registry that `GetStrategy` resolves strategy types from
+func DefaultStrategyRegistry() StrategyRegistry {
+	return strategies
+}
+
+// RegisterStrategy registers a builder of the strategy type to the default registry
+func RegisterStrategy(strategyType string, builder StrategyBuilder) error {
+	return strategies.RegisterStrategy(strategyType, builder)
+}
+
+// StrategyTypes returns the sorted list of the registered strategy types
+func StrategyTypes() []string {
+	var types []string
+	for t := range strategies {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// GetStrategy returns the strategy defined in the config
+// NOTE: the returned strategy doesn't know the path. Use `Path.GetStrategy` if the path is available.
 func GetStrategy(cfg StrategyCfg) (StrategyI, error) {
-	switch cfg.Type {
-	case "naive":
-		return NewNaiveStrategy(), nil
-	default:
+	builder, ok := strategies[cfg.Type]
+	if !ok {
 		return nil, fmt.Errorf("unknown strategy type '%v'", cfg.Type)
 	}
+	return builder(&Path{Strategy: &cfg})
 }
 
 // GetStrategy the strategy defined in the relay messages
 func (p *Path) GetStrategy() (StrategyI, error) {
-	switch p.Strategy.Type {
-	case (&NaiveStrategy{}).GetType():
-		return &NaiveStrategy{}, nil
-	default:
+	if p.Strategy == nil {
+		return nil, fmt.Errorf("strategy is not specified")
+	}
+	builder, ok := strategies[p.Strategy.Type]
+	if !ok {
 		return nil, fmt.Errorf("invalid strategy: %s", p.Strategy.Type)
 	}
+	return builder(p)
 }
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStrategyCfgWithOptionOverrides(t *testing.T) {
+	cases := []struct {
+		name      string
+		options   string
+		overrides string
+		want      string
+		wantErr   bool
+	}{
+		{"no overrides", `{"a":1}`, ``, `{"a":1}`, false},
+		{"no options", ``, `{"a":1}`, `{"a":1}`, false},
+		{"override a field", `{"a":1,"b":"x"}`, `{"a":2}`, `{"a":2,"b":"x"}`, false},
+		{"add a field", `{"a":1}`, `{"c":[1,2]}`, `{"a":1,"c":[1,2]}`, false},
+		{"options not an object", `[1]`, `{"a":1}`, ``, true},
+		{"overrides not an object", `{"a":1}`, `1`, ``, true},
+	}
+	for _, c := range cases {
+		cfg := StrategyCfg{Type: "naive", Options: json.RawMessage(c.options)}
+		got, err := cfg.WithOptionOverrides(json.RawMessage(c.overrides))
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s: returned %v, want error: %v", c.name, err, c.wantErr)
+			continue
+		} else if err != nil {
+			continue
+		}
+		if got.Type != cfg.Type || string(got.Options) != c.want {
+			t.Errorf("%s: got %s %s, want %s", c.name, got.Type, got.Options, c.want)
+		}
+		if string(cfg.Options) != c.options {
+			t.Errorf("%s: the original options are modified to %s", c.name, cfg.Options)
+		}
+	}
+}
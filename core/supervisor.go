@@ -0,0 +1,181 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/yui-relayer/log"
+)
+
+const (
+	restartBackoffMin = time.Second
+	restartBackoffMax = 5 * time.Minute
+)
+
+// Supervisor runs a relay service for each of multiple paths.
+// The paths can share ProvableChain instances, in which case the relays of those paths are serialized
+// and the path info of the shared chains is re-set before each relay.
+type Supervisor struct {
+	paths    []*supervisedPath
+	locks    map[string]*sync.Mutex
+	interval time.Duration
+	opts     []RelayServiceOption
+}
+
+type supervisedPath struct {
+	name    string
+	st      StrategyI
+	binding *pathBinding
+	control *PathControl
+}
+
+// pathBinding binds the path info of a path to the chains while the relay of the path is performed
+type pathBinding struct {
+	path  *Path
+	src   *ProvableChain
+	dst   *ProvableChain
+	locks []*sync.Mutex // sorted by chain ID to prevent deadlocks
+}
+
+// NewSupervisor returns a new supervisor whose relay services are configured with `opts`
+func NewSupervisor(relayInterval time.Duration, opts ...RelayServiceOption) *Supervisor {
+	return &Supervisor{
+		locks:    make(map[string]*sync.Mutex),
+		interval: relayInterval,
+		opts:     opts,
+	}
+}
+
+// AddPath adds a path to be relayed by the supervisor
+func (sv *Supervisor) AddPath(name string, path *Path, src, dst *ProvableChain, st StrategyI) error {
+	for _, p := range sv.paths {
+		if p.name == name {
+			return fmt.Errorf("path %s has already been added", name)
+		}
+	}
+	if src.ChainID() == dst.ChainID() {
+		return fmt.Errorf("path %s has the same chain on both ends: %s", name, src.ChainID())
+	}
+	chainIDs := []string{src.ChainID(), dst.ChainID()}
+	sort.Strings(chainIDs)
+	var locks []*sync.Mutex
+	for _, chainID := range chainIDs {
+		if _, ok := sv.locks[chainID]; !ok {
+			sv.locks[chainID] = new(sync.Mutex)
+		}
+		locks = append(locks, sv.locks[chainID])
+	}
+	sv.paths = append(sv.paths, &supervisedPath{
+		name: name,
+		st:   st,
+		binding: &pathBinding{
+			path:  path,
+			src:   src,
+			dst:   dst,
+			locks: locks,
+		},
+		control: NewPathControl(name, path),
+	})
+	return nil
+}
+
+// PathControls returns the controls of the relay services of the paths in the order they were added
+func (sv *Supervisor) PathControls() []*PathControl {
+	var controls []*PathControl
+	for _, p := range sv.paths {
+		controls = append(controls, p.control)
+	}
+	return controls
+}
+
+// Start starts the relay services of all paths and blocks until `ctx` is done.
+// A relay service that stops with an error is restarted with exponential backoff.
+func (sv *Supervisor) Start(ctx context.Context) error {
+	if len(sv.paths) == 0 {
+		return fmt.Errorf("no paths to relay")
+	}
+	var wg sync.WaitGroup
+	for _, p := range sv.paths {
+		wg.Add(1)
+		go func(p *supervisedPath) {
+			defer wg.Done()
+			sv.run(ctx, p)
+		}(p)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (sv *Supervisor) run(ctx context.Context, p *supervisedPath) {
+	backoff := restartBackoffMin
+	for {
+		startedAt := time.Now()
+		err := sv.startPath(ctx, p)
+		if ctx.Err() != nil {
+			return
+		}
+		// a service that has been running for a while is considered healthy
+		if time.Since(startedAt) > restartBackoffMax {
+			backoff = restartBackoffMin
+		}
+		log.GetLogger().Error("relay service stopped, restarting it", "path", p.name, "error", err, "backoff", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > restartBackoffMax {
+			backoff = restartBackoffMax
+		}
+	}
+}
+
+func (sv *Supervisor) startPath(ctx context.Context, p *supervisedPath) error {
+	// cancel the event subscriptions of the service when it stops
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	b := p.binding
+	if err := b.acquire(); err != nil {
+		return err
+	}
+	if err := p.st.SetupRelay(ctx, b.src, b.dst); err != nil {
+		b.release()
+		return err
+	}
+	sh, err := NewSyncHeaders(ctx, b.src, b.dst)
+	b.release()
+	if err != nil {
+		return err
+	}
+	opts := append([]RelayServiceOption{WithPathControl(p.control)}, sv.opts...)
+	srv := NewRelayService(p.st, b.src, b.dst, sh, sv.interval, opts...)
+	srv.binding = b
+	return srv.Start(ctx)
+}
+
+// acquire locks the chains of the path and sets the path info to them
+func (b *pathBinding) acquire() error {
+	for _, l := range b.locks {
+		l.Lock()
+	}
+	if err := b.src.SetRelayInfo(b.path.Src, b.dst, b.path.Dst); err != nil {
+		b.release()
+		return err
+	}
+	if err := b.dst.SetRelayInfo(b.path.Dst, b.src, b.path.Src); err != nil {
+		b.release()
+		return err
+	}
+	return nil
+}
+
+// release unlocks the chains of the path
+func (b *pathBinding) release() {
+	for i := len(b.locks) - 1; i >= 0; i-- {
+		b.locks[i].Unlock()
+	}
+}
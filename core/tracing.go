@@ -0,0 +1,67 @@
+package core
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates the spans of the relayer. They are discarded unless a tracer provider is set by tracing.InitTracer.
+var tracer = otel.Tracer("github.com/hyperledger-labs/yui-relayer")
+
+// StartSpan starts a span as a child of the span in `ctx`
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartChainSpan starts a span of an operation on the chain as a child of the span in `ctx`
+func StartChainSpan(ctx context.Context, name string, chain Chain, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return StartSpan(ctx, name, append(ChainSpanAttributes(chain), attrs...)...)
+}
+
+// StartQuerySpan starts a span of a query to the chain as a child of the span in the query context
+func StartQuerySpan(ctx QueryContext, name string, chain Chain, attrs ...attribute.KeyValue) trace.Span {
+	attrs = append(attrs, attribute.Int64("height", int64(ctx.Height().GetRevisionHeight())))
+	_, span := StartChainSpan(ctx.Context(), name, chain, attrs...)
+	return span
+}
+
+// EndSpan records `err` in the span if it's not nil and ends the span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ChainSpanAttributes returns the span attributes of the ID of the chain and the identifiers of its path end
+func ChainSpanAttributes(chain Chain) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("chain_id", chain.ChainID())}
+	if pe := chain.Path(); pe != nil {
+		attrs = append(attrs,
+			attribute.String("client_id", pe.ClientID),
+			attribute.String("connection_id", pe.ConnectionID),
+			attribute.String("port_id", pe.PortID),
+			attribute.String("channel_id", pe.ChannelID),
+		)
+	}
+	return attrs
+}
+
+// chainPairSpanAttributes returns the span attributes of the IDs of the chains and the identifiers of their path ends
+func chainPairSpanAttributes(src, dst Chain) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for _, c := range []struct {
+		prefix string
+		chain  Chain
+	}{{"src.", src}, {"dst.", dst}} {
+		for _, kv := range ChainSpanAttributes(c.chain) {
+			attrs = append(attrs, attribute.KeyValue{Key: attribute.Key(c.prefix) + kv.Key, Value: kv.Value})
+		}
+	}
+	return attrs
+}
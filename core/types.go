@@ -1,6 +1,8 @@
 package core
 
 import (
+	"sort"
+
 	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
 	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
 )
@@ -8,11 +10,13 @@ import (
 // PacketInfo represents the packet information that is acquired from a SendPacket event or
 // a pair of RecvPacket/WriteAcknowledgement events. In the former case, the `Acknowledgement`
 // field becomes nil. In the latter case, `EventHeight` represents the height in which the
-// underlying `RecvPacket` event occurs.
+// underlying `RecvPacket` event occurs. `TimedOut` is set by the strategy when the packet
+// can no longer be received on the counterparty chain.
 type PacketInfo struct {
 	chantypes.Packet
 	Acknowledgement []byte             `json:"acknowledgement"`
 	EventHeight     clienttypes.Height `json:"event_height"`
+	TimedOut        bool               `json:"timed_out"`
 }
 
 // PacketInfoList represents a list of PacketInfo that is sorted in the order in which
@@ -40,8 +44,42 @@ func (ps PacketInfoList) Filter(seqs []uint64) PacketInfoList {
 	return ret
 }
 
+// SplitByTimeout splits the list into packets to be received and packets that have timed out
+func (ps PacketInfoList) SplitByTimeout() (receivable, timedOut PacketInfoList) {
+	for _, p := range ps {
+		if p.TimedOut {
+			timedOut = append(timedOut, p)
+		} else {
+			receivable = append(receivable, p)
+		}
+	}
+	return
+}
+
+// Oldest returns the first `n` packets in ascending order of sequence, or all the packets if `n` is zero
+func (ps PacketInfoList) Oldest(n uint64) PacketInfoList {
+	if n == 0 || uint64(len(ps)) <= n {
+		return ps
+	}
+	sorted := make(PacketInfoList, len(ps))
+	copy(sorted, ps)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Sequence < sorted[j].Sequence
+	})
+	return sorted[:n]
+}
+
 // RelayPackets represents unrelayed packets on src and dst
 type RelayPackets struct {
 	Src PacketInfoList `json:"src"`
 	Dst PacketInfoList `json:"dst"`
+	// SrcBacklog and DstBacklog are the numbers of the unrelayed packets before they are filtered and limited.
+	// They are zero if the strategy doesn't count them, in which case the lengths of Src and Dst are the backlog.
+	SrcBacklog int `json:"src_backlog,omitempty"`
+	DstBacklog int `json:"dst_backlog,omitempty"`
+}
+
+// Backlog returns the numbers of the unrelayed packets on src and dst before they are filtered and limited
+func (rp *RelayPackets) Backlog() (src, dst int) {
+	return max(rp.SrcBacklog, len(rp.Src)), max(rp.DstBacklog, len(rp.Dst))
 }
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	chantypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
+)
+
+func packetList(seqs ...uint64) PacketInfoList {
+	var ps PacketInfoList
+	for _, seq := range seqs {
+		ps = append(ps, &PacketInfo{Packet: chantypes.Packet{Sequence: seq}})
+	}
+	return ps
+}
+
+func TestPacketInfoListOldest(t *testing.T) {
+	cases := []struct {
+		name string
+		seqs []uint64
+		n    uint64
+		want []uint64
+	}{
+		{"no limit", []uint64{3, 1, 2}, 0, []uint64{3, 1, 2}},
+		{"limit above length", []uint64{3, 1, 2}, 5, []uint64{3, 1, 2}},
+		{"limit equal to length", []uint64{3, 1, 2}, 3, []uint64{3, 1, 2}},
+		{"oldest first", []uint64{5, 3, 4, 1, 2}, 2, []uint64{1, 2}},
+		{"empty", nil, 2, nil},
+	}
+	for _, c := range cases {
+		ps := packetList(c.seqs...)
+		if got := ps.Oldest(c.n).ExtractSequenceList(); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+		if got := ps.ExtractSequenceList(); !reflect.DeepEqual(got, c.seqs) {
+			t.Errorf("%s: the list is modified to %v", c.name, got)
+		}
+	}
+}
+
+func TestPacketInfoListSplitByTimeout(t *testing.T) {
+	cases := []struct {
+		name           string
+		timedOut       map[uint64]bool
+		seqs           []uint64
+		wantReceivable []uint64
+		wantTimedOut   []uint64
+	}{
+		{"none timed out", nil, []uint64{1, 2}, []uint64{1, 2}, nil},
+		{"all timed out", map[uint64]bool{1: true, 2: true}, []uint64{1, 2}, nil, []uint64{1, 2}},
+		{"mixed keeps order", map[uint64]bool{2: true, 4: true}, []uint64{4, 1, 2, 3}, []uint64{1, 3}, []uint64{4, 2}},
+		{"empty", nil, nil, nil, nil},
+	}
+	for _, c := range cases {
+		ps := packetList(c.seqs...)
+		for _, p := range ps {
+			p.TimedOut = c.timedOut[p.Sequence]
+		}
+		receivable, timedOut := ps.SplitByTimeout()
+		if got := receivable.ExtractSequenceList(); !reflect.DeepEqual(got, c.wantReceivable) {
+			t.Errorf("%s: receivable %v, want %v", c.name, got, c.wantReceivable)
+		}
+		if got := timedOut.ExtractSequenceList(); !reflect.DeepEqual(got, c.wantTimedOut) {
+			t.Errorf("%s: timed out %v, want %v", c.name, got, c.wantTimedOut)
+		}
+	}
+}
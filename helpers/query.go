@@ -4,10 +4,14 @@ import (
 	"context"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
 	ibcexported "github.com/cosmos/ibc-go/v7/modules/core/exported"
 	"github.com/hyperledger-labs/yui-relayer/core"
 )
 
+// denomTracesPageLimit is the number of denom traces fetched per query
+const denomTracesPageLimit = 100
+
 // QueryBalance is a helper function for query balance
 func QueryBalance(chain *core.ProvableChain, height ibcexported.Height, address sdk.AccAddress, showDenoms bool) (sdk.Coins, error) {
 	ctx := core.NewQueryContext(context.TODO(), height)
@@ -20,12 +24,19 @@ func QueryBalance(chain *core.ProvableChain, height ibcexported.Height, address
 		return coins, nil
 	}
 
-	dts, err := chain.QueryDenomTraces(ctx, 0, 1000)
-	if err != nil {
-		return nil, err
+	var traces transfertypes.Traces
+	for {
+		dts, err := chain.QueryDenomTraces(ctx, uint64(len(traces)), denomTracesPageLimit)
+		if err != nil {
+			return nil, err
+		}
+		traces = append(traces, dts.DenomTraces...)
+		if len(dts.DenomTraces) == 0 || len(dts.Pagination.GetNextKey()) == 0 {
+			break
+		}
 	}
 
-	if len(dts.DenomTraces) == 0 {
+	if len(traces) == 0 {
 		return coins, nil
 	}
 
@@ -35,13 +46,13 @@ func QueryBalance(chain *core.ProvableChain, height ibcexported.Height, address
 			continue
 		}
 
-		for i, d := range dts.DenomTraces {
+		for i, d := range traces {
 			if c.Denom == d.IBCDenom() {
 				out = append(out, sdk.Coin{Denom: d.GetFullDenomPath(), Amount: c.Amount})
 				break
 			}
 
-			if i == len(dts.DenomTraces)-1 {
+			if i == len(traces)-1 {
 				out = append(out, c)
 			}
 		}
@@ -0,0 +1,61 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+var relayLogger atomic.Pointer[slog.Logger]
+
+func init() {
+	relayLogger.Store(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+}
+
+// InitLogger configures the logger of the relayer.
+// `level` is one of "debug", "info", "warn" and "error", `format` is "text" or "json",
+// and `output` is "stdout", "stderr" or the path of a file to which the logs are appended.
+func InitLogger(level, format, output string) error {
+	if level == "" {
+		level = "info"
+	}
+	var lv slog.Level
+	if err := lv.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level '%s': %w", level, err)
+	}
+
+	var w io.Writer
+	switch output {
+	case "", "stderr":
+		w = os.Stderr
+	case "stdout":
+		w = os.Stdout
+	default:
+		f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open the log file: %w", err)
+		}
+		w = f
+	}
+
+	opts := &slog.HandlerOptions{Level: lv}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", "text":
+		handler = slog.NewTextHandler(w, opts)
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid log format '%s'", format)
+	}
+	relayLogger.Store(slog.New(handler))
+	return nil
+}
+
+// GetLogger returns the logger of the relayer
+func GetLogger() *slog.Logger {
+	return relayLogger.Load()
+}
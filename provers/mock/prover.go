@@ -3,6 +3,7 @@ package mock
 import (
 	"context"
 	"crypto/sha256"
+	"log/slog"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/codec"
@@ -23,7 +24,7 @@ func NewProver(chain core.Chain) *Prover {
 	return &Prover{chain: chain}
 }
 
-func (pr *Prover) Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, debug bool) error {
+func (pr *Prover) Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, logger *slog.Logger, debug bool) error {
 	return nil
 }
 
@@ -53,13 +54,13 @@ func (pr *Prover) CreateMsgCreateClient(clientID string, dstHeader core.Header,
 }
 
 // SetupHeadersForUpdate returns the finalized header and any intermediate headers needed to apply it to the client on the counterpaty chain
-func (pr *Prover) SetupHeadersForUpdate(dstChain core.ChainInfoICS02Querier, latestFinalizedHeader core.Header) ([]core.Header, error) {
+func (pr *Prover) SetupHeadersForUpdate(ctx context.Context, dstChain core.ChainInfoICS02Querier, latestFinalizedHeader core.Header) ([]core.Header, error) {
 	return []core.Header{latestFinalizedHeader.(*mocktypes.Header)}, nil
 }
 
 // GetLatestFinalizedHeader returns the latest finalized header
-func (pr *Prover) GetLatestFinalizedHeader() (latestFinalizedHeader core.Header, err error) {
-	chainLatestHeight, err := pr.chain.LatestHeight()
+func (pr *Prover) GetLatestFinalizedHeader(ctx context.Context) (latestFinalizedHeader core.Header, err error) {
+	chainLatestHeight, err := pr.chain.LatestHeight(ctx)
 	if err != nil {
 		return nil, err
 	}
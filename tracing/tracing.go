@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+const serviceName = "yui-relayer"
+
+// InitTracer sets the global tracer provider that exports the spans with `exporter`.
+// `exporter` is one of "otlp", "stdout" and "file", and tracing stays disabled if it's empty.
+// `endpoint` is the OTLP/gRPC endpoint (e.g. localhost:4317) for "otlp" and the path of a file to which the spans are appended for "file".
+// The returned function flushes the pending spans and shuts the provider down.
+func InitTracer(ctx context.Context, exporter, endpoint string, insecure bool) (func(context.Context) error, error) {
+	var exp sdktrace.SpanExporter
+	switch exporter {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+		opts := []otlptracegrpc.Option{}
+		if endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		e, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the OTLP trace exporter: %w", err)
+		}
+		exp = e
+	case "stdout", "file":
+		var w io.Writer = os.Stdout
+		if exporter == "file" {
+			if endpoint == "" {
+				return nil, fmt.Errorf("the file to export the traces to is not specified")
+			}
+			f, err := os.OpenFile(endpoint, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open the trace file: %w", err)
+			}
+			w = f
+		}
+		e, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the stdout trace exporter: %w", err)
+		}
+		exp = e
+	default:
+		return nil, fmt.Errorf("invalid trace exporter '%s'", exporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}